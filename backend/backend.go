@@ -0,0 +1,87 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package backend
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/cache"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/vdir"
+)
+
+// Backend stores the calendars of a server. The commands only access the calendars through it, so they don't depend
+// on the protocol or storage behind them
+type Backend interface {
+	// Calendars discovers the calendars of the server
+	Calendars() ([]Calendar, error)
+	// Query returns the objects of a calendar matching the query
+	Query(calendarPath string, query Query) ([]caldav.CalendarObject, error)
+	// Object returns the object at the given path
+	Object(path string) (*caldav.CalendarObject, error)
+	// Create stores a new object, failing if it already exists. It returns the ETag of the object, when known
+	Create(path string, cal *ical.Calendar) (string, error)
+	// Update replaces an object, only if its ETag still matches. Without an ETag, the object is replaced unconditionally
+	Update(path string, cal *ical.Calendar, etag string) (string, error)
+	// Delete removes an object, only if its ETag still matches when one is given
+	Delete(path string, etag string) error
+	// Sync brings the cache of a calendar up to date
+	Sync(calendar *cache.Calendar, calendarPath string) error
+}
+
+// Scheduler is implemented by the backends that support RFC 6638 scheduling and free-busy queries
+type Scheduler interface {
+	// Scheduling returns the scheduling properties of the current user
+	Scheduling() (*dav.Scheduling, error)
+	// FreeBusy returns the busy time of a calendar as a VFREEBUSY component
+	FreeBusy(calendarPath string, start time.Time, end time.Time) (*ical.Calendar, error)
+	// PostOutbox sends a scheduling message to the outbox, and returns the answer for each recipient
+	PostOutbox(outbox string, cal *ical.Calendar) ([]dav.ScheduleResponse, error)
+}
+
+// Mover is implemented by the backends that can move an object between their calendars without copying it
+type Mover interface {
+	// Move moves an object to another path, failing if the destination exists or the ETag doesn't match
+	Move(path string, destination string, etag string) error
+}
+
+// Calendar is a calendar found on a server
+type Calendar struct {
+	Path        string
+	Name        string
+	Description string
+	Color       string   // the color set on the server, if any
+	Components  []string // the supported components, all of them when empty
+}
+
+// Query selects the objects containing a component, e.g. VEVENT, between start and end. Zero times leave the range
+// open, and an empty component matches all the objects. The range and the filters are a hint: backends that can't
+// apply them return more objects, so callers must filter the result too
+type Query struct {
+	Component string
+	Start     time.Time
+	End       time.Time
+	Filters   []dav.PropFilter
+}
+
+// ErrReadOnly is returned when writing to a calendar that can't be modified, such as an ICS feed
+var ErrReadOnly = errors.New("the calendar is read-only")
+
+// IsConflict reports whether a write failed because the object was modified since it was read, or already exists
+func IsConflict(err error) bool {
+	return dav.IsPreconditionFailed(err) || errors.Is(err, vdir.ErrModified)
+}
@@ -0,0 +1,351 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+// Package backendtest provides an in-memory backend, to test the commands without a server
+package backendtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/cache"
+	"tsundoku.dev/quickcal/constants"
+	"tsundoku.dev/quickcal/dav"
+)
+
+// errConflict is returned when an object was modified since it was read, or already exists, like a CalDAV server does
+var errConflict = &dav.HTTPError{StatusCode: http.StatusPreconditionFailed, Status: "412 Precondition Failed"}
+
+// Memory keeps its calendars in memory. It supports scheduling once SetScheduling is called
+type Memory struct {
+	// Unreachable makes every request fail with a network error, as if the server was down
+	Unreachable bool
+	// Outbox contains the messages posted to the scheduling outbox
+	Outbox []*ical.Calendar
+
+	mu         sync.Mutex
+	calendars  []backend.Calendar
+	objects    map[string]memoryObject
+	scheduling *dav.Scheduling
+	lastETag   int
+}
+
+// memoryObject is a stored object, encoded so later changes of the caller's calendar don't modify it
+type memoryObject struct {
+	etag string
+	data []byte
+}
+
+// NewMemory returns an in-memory backend with the given empty calendars
+func NewMemory(calendars ...backend.Calendar) *Memory {
+	return &Memory{calendars: calendars, objects: make(map[string]memoryObject)}
+}
+
+// SetScheduling enables scheduling, with the given inbox, outbox and addresses
+func (b *Memory) SetScheduling(scheduling *dav.Scheduling) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.scheduling = scheduling
+}
+
+func (b *Memory) Calendars() ([]backend.Calendar, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	return append([]backend.Calendar(nil), b.calendars...), nil
+}
+
+// Query returns the objects of a calendar containing the component, regardless of the range and the filters
+func (b *Memory) Query(calendarPath string, query backend.Query) ([]caldav.CalendarObject, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	paths := make([]string, 0)
+	for objectPath := range b.objects {
+		if path.Dir(objectPath)+"/" == calendarPath {
+			paths = append(paths, objectPath)
+		}
+	}
+	sort.Strings(paths)
+
+	objects := make([]caldav.CalendarObject, 0, len(paths))
+	for _, objectPath := range paths {
+		object, err := b.decode(objectPath)
+		if err != nil {
+			return nil, err
+		}
+		if query.Component == "" || hasComponent(object.Data, query.Component) {
+			objects = append(objects, *object)
+		}
+	}
+
+	return objects, nil
+}
+
+func (b *Memory) Object(path string) (*caldav.CalendarObject, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.objects[path]; !ok {
+		return nil, fmt.Errorf("object '%s' not found", path)
+	}
+
+	return b.decode(path)
+}
+
+func (b *Memory) Create(path string, cal *ical.Calendar) (string, error) {
+	if err := b.check(); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.objects[path]; ok {
+		return "", errConflict
+	}
+
+	return b.put(path, cal)
+}
+
+func (b *Memory) Update(path string, cal *ical.Calendar, etag string) (string, error) {
+	if err := b.check(); err != nil {
+		return "", err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.checkETag(path, etag); err != nil {
+		return "", err
+	}
+
+	return b.put(path, cal)
+}
+
+func (b *Memory) Delete(path string, etag string) error {
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.objects[path]; !ok {
+		return fmt.Errorf("object '%s' not found", path)
+	}
+	if err := b.checkETag(path, etag); err != nil {
+		return err
+	}
+
+	delete(b.objects, path)
+	return nil
+}
+
+func (b *Memory) Move(path string, destination string, etag string) error {
+	if err := b.check(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	object, ok := b.objects[path]
+	if !ok {
+		return fmt.Errorf("object '%s' not found", path)
+	}
+	if err := b.checkETag(path, etag); err != nil {
+		return err
+	}
+	if _, ok := b.objects[destination]; ok {
+		return errConflict
+	}
+
+	b.objects[destination] = object
+	delete(b.objects, path)
+	return nil
+}
+
+// Sync replaces the cached objects with all the objects of the calendar
+func (b *Memory) Sync(calendar *cache.Calendar, calendarPath string) error {
+
+	objects, err := b.Query(calendarPath, backend.Query{})
+	if err != nil {
+		return err
+	}
+
+	return calendar.Replace(objects)
+}
+
+func (b *Memory) Scheduling() (*dav.Scheduling, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.scheduling == nil {
+		return nil, errors.New("scheduling is not supported")
+	}
+
+	return b.scheduling, nil
+}
+
+// FreeBusy returns the busy periods of the events of a calendar, recurrent events only count once
+func (b *Memory) FreeBusy(calendarPath string, start time.Time, end time.Time) (*ical.Calendar, error) {
+
+	objects, err := b.Query(calendarPath, backend.Query{Component: ical.CompEvent})
+	if err != nil {
+		return nil, err
+	}
+
+	freeBusy := ical.NewComponent(ical.CompFreeBusy)
+	for _, object := range objects {
+		for _, child := range object.Data.Children {
+			if child.Name != ical.CompEvent {
+				continue
+			}
+			if transparency := child.Props.Get(ical.PropTransparency); transparency != nil && strings.EqualFold(transparency.Value, "TRANSPARENT") {
+				continue
+			}
+
+			eventStart, err := child.Props.DateTime(ical.PropDateTimeStart, time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			eventEnd, err := child.Props.DateTime(ical.PropDateTimeEnd, time.UTC)
+			if err != nil || eventEnd.IsZero() {
+				eventEnd = eventStart
+			}
+			if !eventStart.Before(end) || !eventEnd.After(start) {
+				continue
+			}
+
+			freeBusy.Props.Add(&ical.Prop{
+				Name:   ical.PropFreeBusy,
+				Params: ical.Params{},
+				Value:  eventStart.UTC().Format(constants.TimeLayoutICalDateTimeUTC) + "/" + eventEnd.UTC().Format(constants.TimeLayoutICalDateTimeUTC),
+			})
+		}
+	}
+
+	cal := ical.NewCalendar()
+	cal.Children = append(cal.Children, freeBusy)
+
+	return cal, nil
+}
+
+// PostOutbox keeps the message in Outbox, and answers that it was delivered to every attendee
+func (b *Memory) PostOutbox(outbox string, cal *ical.Calendar) ([]dav.ScheduleResponse, error) {
+	if err := b.check(); err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.Outbox = append(b.Outbox, cal)
+
+	responses := make([]dav.ScheduleResponse, 0)
+	for _, child := range cal.Children {
+		for _, attendee := range child.Props.Values(ical.PropAttendee) {
+			responses = append(responses, dav.ScheduleResponse{Recipient: attendee.Value, RequestStatus: "2.0;Success"})
+		}
+	}
+
+	return responses, nil
+}
+
+// check fails like an unreachable server when Unreachable is set
+func (b *Memory) check() error {
+	if b.Unreachable {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("the server is unreachable")}
+	}
+
+	return nil
+}
+
+func (b *Memory) checkETag(path string, etag string) error {
+
+	object, ok := b.objects[path]
+	if etag != "" && (!ok || object.etag != etag) {
+		return errConflict
+	}
+
+	return nil
+}
+
+func (b *Memory) put(path string, cal *ical.Calendar) (string, error) {
+
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return "", err
+	}
+
+	b.lastETag++
+	etag := strconv.Itoa(b.lastETag)
+	b.objects[path] = memoryObject{etag: etag, data: buf.Bytes()}
+
+	return etag, nil
+}
+
+func (b *Memory) decode(path string) (*caldav.CalendarObject, error) {
+
+	object := b.objects[path]
+	cal, err := ical.NewDecoder(bytes.NewReader(object.data)).Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &caldav.CalendarObject{Path: path, ETag: object.etag, Data: cal}, nil
+}
+
+func hasComponent(cal *ical.Calendar, name string) bool {
+
+	for _, child := range cal.Children {
+		if child.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	_ backend.Backend   = (*Memory)(nil)
+	_ backend.Scheduler = (*Memory)(nil)
+	_ backend.Mover     = (*Memory)(nil)
+)
@@ -0,0 +1,152 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package backend
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/cache"
+	"tsundoku.dev/quickcal/dav"
+)
+
+// CalDAV is a CalDAV server
+type CalDAV struct {
+	client *caldav.Client
+	dav    *dav.Client
+}
+
+// NewCalDAV returns the backend of the CalDAV server at the given URL, using basic authentication
+func NewCalDAV(url string, user string, password string) (*CalDAV, error) {
+
+	httpClient := webdav.HTTPClientWithBasicAuth(nil, user, password)
+
+	client, err := caldav.NewClient(httpClient, url)
+	if err != nil {
+		return nil, err
+	}
+
+	davClient, err := dav.NewClient(httpClient, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CalDAV{client: client, dav: davClient}, nil
+}
+
+// Calendars returns the calendars of the calendar home set of the current user
+func (b *CalDAV) Calendars() ([]Calendar, error) {
+
+	principal, err := b.client.FindCurrentUserPrincipal()
+	if err != nil {
+		return nil, err
+	}
+
+	homeset, err := b.client.FindCalendarHomeSet(principal)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := b.client.FindCalendars(homeset)
+	if err != nil {
+		return nil, err
+	}
+
+	// not every server supports the calendar-color property, so the colors are optional
+	colors, err := b.dav.CalendarColors(homeset)
+	if err != nil {
+		colors = make(map[string]string)
+	}
+
+	calendars := make([]Calendar, 0, len(found))
+	for _, calendar := range found {
+		calendars = append(calendars, Calendar{
+			Path:        calendar.Path,
+			Name:        calendar.Name,
+			Description: calendar.Description,
+			Color:       colors[calendar.Path],
+			Components:  calendar.SupportedComponentSet,
+		})
+	}
+
+	return calendars, nil
+}
+
+// Query sends a calendar-query REPORT. When the server rejects the prop filters, the query is sent again without them
+func (b *CalDAV) Query(calendarPath string, query Query) ([]caldav.CalendarObject, error) {
+
+	if len(query.Filters) > 0 {
+		objects, err := b.dav.QueryCalendar(calendarPath, query.Component, query.Start, query.End, query.Filters)
+
+		var httpErr *dav.HTTPError
+		if err == nil || !errors.As(err, &httpErr) {
+			return objects, err
+		}
+	}
+
+	compFilter := caldav.CompFilter{Name: ical.CompCalendar}
+	if query.Component != "" {
+		compFilter.Comps = []caldav.CompFilter{{Name: query.Component, Start: query.Start, End: query.End}}
+	}
+
+	return b.client.QueryCalendar(calendarPath, &caldav.CalendarQuery{CompFilter: compFilter})
+}
+
+func (b *CalDAV) Object(path string) (*caldav.CalendarObject, error) {
+	return b.client.GetCalendarObject(path)
+}
+
+func (b *CalDAV) Create(path string, cal *ical.Calendar) (string, error) {
+	return b.dav.CreateCalendarObject(path, cal)
+}
+
+func (b *CalDAV) Update(path string, cal *ical.Calendar, etag string) (string, error) {
+	return b.dav.UpdateCalendarObject(path, cal, etag)
+}
+
+func (b *CalDAV) Delete(path string, etag string) error {
+	return b.dav.DeleteCalendarObject(path, etag)
+}
+
+// Move sends a WebDAV MOVE request
+func (b *CalDAV) Move(path string, destination string, etag string) error {
+	return b.dav.MoveCalendarObject(path, destination, etag)
+}
+
+// Sync downloads the objects that changed since the last sync of the cache
+func (b *CalDAV) Sync(calendar *cache.Calendar, calendarPath string) error {
+	return calendar.Sync(b.dav, calendarPath)
+}
+
+// Scheduling returns the scheduling inbox, outbox and addresses of the current user
+func (b *CalDAV) Scheduling() (*dav.Scheduling, error) {
+
+	principal, err := b.client.FindCurrentUserPrincipal()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.dav.SchedulingInfo(principal)
+}
+
+func (b *CalDAV) FreeBusy(calendarPath string, start time.Time, end time.Time) (*ical.Calendar, error) {
+	return b.dav.FreeBusyQuery(calendarPath, start, end)
+}
+
+func (b *CalDAV) PostOutbox(outbox string, cal *ical.Calendar) ([]dav.ScheduleResponse, error) {
+	return b.dav.PostOutbox(outbox, cal)
+}
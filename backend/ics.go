@@ -0,0 +1,265 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/cache"
+)
+
+// ICS is a read-only iCalendar feed, published at an http(s) or webcal URL, or stored in a local file. The feed is a
+// single calendar, whose objects are the components grouped by UID
+type ICS struct {
+	url  string // empty for local files
+	file string
+	http *http.Client
+}
+
+// NewICS returns the backend of the feed at the given URL. webcal URLs are fetched with https, and file URLs or
+// paths, with a leading ~ expanded to the home directory, are read from the disk
+func NewICS(feedURL string) (*ICS, error) {
+
+	b := &ICS{http: &http.Client{Timeout: 30 * time.Second}}
+
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		b.url = feedURL
+	case "webcal", "webcals":
+		u.Scheme = "https"
+		b.url = u.String()
+	case "file":
+		b.file = u.Path
+	case "":
+		b.file = feedURL
+		if feedURL == "~" || strings.HasPrefix(feedURL, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, err
+			}
+			b.file = filepath.Join(home, feedURL[1:])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported feed URL '%s'", feedURL)
+	}
+
+	return b, nil
+}
+
+// Calendars returns the feed, named after its X-WR-CALNAME property
+func (b *ICS) Calendars() ([]Calendar, error) {
+
+	feed, _, _, err := b.fetch("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := Calendar{Path: "/"}
+	if name := feed.Props.Get("X-WR-CALNAME"); name != nil {
+		calendar.Name = name.Value
+	}
+	if color := feed.Props.Get("X-APPLE-CALENDAR-COLOR"); color != nil {
+		calendar.Color = color.Value
+	}
+
+	return []Calendar{calendar}, nil
+}
+
+// Query downloads the feed and returns the objects containing the component, regardless of the range
+func (b *ICS) Query(calendarPath string, query Query) ([]caldav.CalendarObject, error) {
+
+	feed, _, _, err := b.fetch("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]caldav.CalendarObject, 0)
+	for _, object := range feedObjects(feed) {
+		if query.Component == "" || hasComponent(object.Data, query.Component) {
+			matching = append(matching, object)
+		}
+	}
+
+	return matching, nil
+}
+
+func (b *ICS) Object(path string) (*caldav.CalendarObject, error) {
+
+	feed, _, _, err := b.fetch("", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, object := range feedObjects(feed) {
+		if object.Path == path {
+			return &object, nil
+		}
+	}
+
+	return nil, fmt.Errorf("object '%s' not found in the feed", path)
+}
+
+func (b *ICS) Create(path string, cal *ical.Calendar) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (b *ICS) Update(path string, cal *ical.Calendar, etag string) (string, error) {
+	return "", ErrReadOnly
+}
+
+func (b *ICS) Delete(path string, etag string) error {
+	return ErrReadOnly
+}
+
+// Sync downloads the feed again, unless it wasn't modified since the last sync. The ETag of the feed is kept as the
+// ctag of the cache
+func (b *ICS) Sync(calendar *cache.Calendar, calendarPath string) error {
+
+	feed, etag, lastModified, err := b.fetch(calendar.CTag, calendar.LastModified)
+	if err != nil {
+		return err
+	}
+
+	if feed != nil {
+		if err := calendar.Replace(feedObjects(feed)); err != nil {
+			return err
+		}
+		calendar.CTag = etag
+		calendar.LastModified = lastModified
+	}
+
+	calendar.LastSync = time.Now()
+	return nil
+}
+
+// fetch reads the feed, with a conditional GET when the ETag or the modification time of the previous download are
+// given. It returns a nil calendar when the feed wasn't modified, and the ETag and modification time of the feed
+func (b *ICS) fetch(etag string, lastModified string) (*ical.Calendar, string, string, error) {
+
+	if b.file != "" {
+		info, err := os.Stat(b.file)
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		modified := info.ModTime().UTC().Format(http.TimeFormat)
+		if modified == lastModified {
+			return nil, "", modified, nil
+		}
+
+		data, err := os.ReadFile(b.file)
+		if err != nil {
+			return nil, "", "", err
+		}
+
+		feed, err := ical.NewDecoder(bytes.NewReader(data)).Decode()
+		return feed, "", modified, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, b.url, nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	req.Header.Set("Accept", ical.MIMEType)
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, etag, lastModified, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("failed to fetch '%s': %s", b.url, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	feed, err := ical.NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return feed, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), nil
+}
+
+// feedObjects splits a feed into an object per UID, like a CalDAV calendar stores it. The time zones of the feed are
+// copied into every object
+func feedObjects(feed *ical.Calendar) []caldav.CalendarObject {
+
+	var timezones []*ical.Component
+	for _, child := range feed.Children {
+		if child.Name == ical.CompTimezone {
+			timezones = append(timezones, child)
+		}
+	}
+
+	objects := make([]caldav.CalendarObject, 0)
+	byUID := make(map[string]*ical.Calendar)
+	for i, child := range feed.Children {
+		if child.Name == ical.CompTimezone {
+			continue
+		}
+
+		uid := fmt.Sprintf("component-%d", i)
+		if prop := child.Props.Get(ical.PropUID); prop != nil && prop.Value != "" {
+			uid = prop.Value
+		}
+
+		// recurrence overrides share the UID of their event
+		if cal, ok := byUID[uid]; ok {
+			cal.Children = append(cal.Children, child)
+			continue
+		}
+
+		cal := ical.NewCalendar()
+		cal.Props.SetText(ical.PropVersion, "2.0")
+		cal.Props.SetText(ical.PropProductID, "-//QuickCal//CalDAV Client//EN")
+		if prodID := feed.Props.Get(ical.PropProductID); prodID != nil {
+			cal.Props.SetText(ical.PropProductID, prodID.Value)
+		}
+		cal.Children = append(append(cal.Children, timezones...), child)
+		byUID[uid] = cal
+
+		objects = append(objects, caldav.CalendarObject{Path: "/" + url.PathEscape(uid) + ".ics", Data: cal})
+	}
+
+	return objects
+}
@@ -0,0 +1,116 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package backend
+
+import (
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/cache"
+	"tsundoku.dev/quickcal/vdir"
+)
+
+// Vdir is a local vdir storage. The queries return every object containing the component, regardless of the range
+type Vdir struct {
+	storage *vdir.Storage
+}
+
+// NewVdir returns the backend of the vdir at the given path
+func NewVdir(path string) (*Vdir, error) {
+
+	storage, err := vdir.New(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Vdir{storage: storage}, nil
+}
+
+// Calendars returns the calendar directories, with the color written by vdirsyncer
+func (b *Vdir) Calendars() ([]Calendar, error) {
+
+	dirs, err := b.storage.Calendars()
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := make([]Calendar, 0, len(dirs))
+	for _, dir := range dirs {
+		calendars = append(calendars, Calendar{Path: dir.Path, Name: dir.Name, Color: dir.Color})
+	}
+
+	return calendars, nil
+}
+
+func (b *Vdir) Query(calendarPath string, query Query) ([]caldav.CalendarObject, error) {
+
+	objects, err := b.storage.Objects(calendarPath)
+	if err != nil {
+		return nil, err
+	}
+	if query.Component == "" {
+		return objects, nil
+	}
+
+	matching := make([]caldav.CalendarObject, 0, len(objects))
+	for _, object := range objects {
+		if hasComponent(object.Data, query.Component) {
+			matching = append(matching, object)
+		}
+	}
+
+	return matching, nil
+}
+
+func (b *Vdir) Object(path string) (*caldav.CalendarObject, error) {
+	return b.storage.Object(path)
+}
+
+func (b *Vdir) Create(path string, cal *ical.Calendar) (string, error) {
+	return b.storage.Create(path, cal)
+}
+
+func (b *Vdir) Update(path string, cal *ical.Calendar, etag string) (string, error) {
+	return b.storage.Update(path, cal, etag)
+}
+
+func (b *Vdir) Delete(path string, etag string) error {
+	return b.storage.Delete(path, etag)
+}
+
+func (b *Vdir) Move(path string, destination string, etag string) error {
+	return b.storage.Move(path, destination, etag)
+}
+
+// Sync reads the calendar directory again. The files are local, so there is nothing to download
+func (b *Vdir) Sync(calendar *cache.Calendar, calendarPath string) error {
+
+	objects, err := b.storage.Objects(calendarPath)
+	if err != nil {
+		return err
+	}
+
+	return calendar.Replace(objects)
+}
+
+// hasComponent reports whether a calendar contains a component with the given name
+func hasComponent(cal *ical.Calendar, name string) bool {
+
+	for _, child := range cal.Children {
+		if child.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
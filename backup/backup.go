@@ -0,0 +1,274 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestName is the file listing the objects of a backup
+const manifestName = "manifest.json"
+
+// Entry describes a backed up calendar object
+type Entry struct {
+	Server       string `json:"server"`
+	Calendar     string `json:"calendar"`
+	CalendarPath string `json:"calendarPath"`
+	Href         string `json:"href"` // the path of the object on the server
+	ETag         string `json:"etag,omitempty"`
+	File         string `json:"file"` // the path of the object in the backup
+}
+
+// Object is a backed up calendar object, with its iCalendar data
+type Object struct {
+	Entry
+	Data []byte
+}
+
+type manifest struct {
+	Created time.Time `json:"created"`
+	Entries []Entry   `json:"entries"`
+}
+
+// Writer writes a backup. The format depends on the extension of the output: .zip, .tar, .tar.gz or .tgz, and a
+// directory otherwise
+type Writer struct {
+	output  string
+	entries []Entry
+	file    *os.File
+	gzip    *gzip.Writer
+	tar     *tar.Writer
+	zip     *zip.Writer
+	created time.Time
+}
+
+// Create starts a backup at the given path
+func Create(output string) (*Writer, error) {
+
+	w := &Writer{output: output, entries: make([]Entry, 0), created: time.Now()}
+
+	if format(output) == "dir" {
+		return w, os.MkdirAll(output, 0o700)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return nil, err
+	}
+	w.file = file
+
+	switch format(output) {
+	case "zip":
+		w.zip = zip.NewWriter(file)
+	case "tgz":
+		w.gzip = gzip.NewWriter(file)
+		w.tar = tar.NewWriter(w.gzip)
+	case "tar":
+		w.tar = tar.NewWriter(file)
+	}
+
+	return w, nil
+}
+
+// Add writes an object, named after its server, calendar and href
+func (w *Writer) Add(entry Entry, data []byte) error {
+
+	entry.File = path.Join(url.PathEscape(entry.Server), url.PathEscape(strings.Trim(entry.CalendarPath, "/")), url.PathEscape(path.Base(entry.Href)))
+	w.entries = append(w.entries, entry)
+
+	return w.write(entry.File, data)
+}
+
+// Close writes the manifest and closes the backup
+func (w *Writer) Close() error {
+
+	data, err := json.MarshalIndent(manifest{Created: w.created, Entries: w.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := w.write(manifestName, data); err != nil {
+		return err
+	}
+
+	switch {
+	case w.zip != nil:
+		err = w.zip.Close()
+	case w.tar != nil:
+		err = w.tar.Close()
+		if err == nil && w.gzip != nil {
+			err = w.gzip.Close()
+		}
+	}
+
+	if w.file != nil {
+		if closeErr := w.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func (w *Writer) write(name string, data []byte) error {
+
+	switch {
+	case w.zip != nil:
+		fw, err := w.zip.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: w.created})
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(data)
+		return err
+
+	case w.tar != nil:
+		err := w.tar.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(data)), ModTime: w.created})
+		if err != nil {
+			return err
+		}
+		_, err = w.tar.Write(data)
+		return err
+	}
+
+	file := filepath.Join(w.output, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, data, 0o600)
+}
+
+// Read returns the objects of a backup, in the order they were backed up
+func Read(input string) ([]Object, error) {
+
+	files := make(map[string][]byte)
+	readAll := func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		files[name] = data
+		return err
+	}
+
+	switch format(input) {
+	case "dir":
+		data, err := os.ReadFile(filepath.Join(input, manifestName))
+		if err != nil {
+			return nil, err
+		}
+		files[manifestName] = data
+
+	case "zip":
+		zr, err := zip.OpenReader(input)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = readAll(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		file, err := os.Open(input)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		var r io.Reader = file
+		if format(input) == "tgz" {
+			gr, err := gzip.NewReader(file)
+			if err != nil {
+				return nil, err
+			}
+			r = gr
+		}
+
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			if err := readAll(header.Name, tr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	data, ok := files[manifestName]
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a backup, it has no %s", input, manifestName)
+	}
+
+	var m manifest
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&m); err != nil {
+		return nil, err
+	}
+
+	objects := make([]Object, 0, len(m.Entries))
+	for _, entry := range m.Entries {
+		data, ok := files[entry.File]
+		if !ok && format(input) == "dir" {
+			var err error
+			data, err = os.ReadFile(filepath.Join(input, filepath.FromSlash(entry.File)))
+			ok = err == nil
+		}
+		if !ok {
+			return nil, fmt.Errorf("object '%s' is missing from the backup", entry.File)
+		}
+
+		objects = append(objects, Object{Entry: entry, Data: data})
+	}
+
+	return objects, nil
+}
+
+// format returns the format of a backup from its extension
+func format(name string) string {
+
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	}
+
+	return "dir"
+}
@@ -0,0 +1,67 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoundTrip(t *testing.T) {
+
+	entries := []Entry{
+		{Server: "home", Calendar: "Personal", CalendarPath: "/cal/personal/", Href: "/cal/personal/a.ics", ETag: "1"},
+		{Server: "home", Calendar: "Work", CalendarPath: "/cal/work/", Href: "/cal/work/b c.ics"},
+	}
+	data := [][]byte{[]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), []byte("other")}
+
+	for _, name := range []string{"backup", "backup.zip", "backup.tar", "backup.tar.gz", "backup.tgz"} {
+		t.Run(name, func(t *testing.T) {
+
+			output := filepath.Join(t.TempDir(), name)
+			w, err := Create(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, entry := range entries {
+				if err := w.Add(entry, data[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			objects, err := Read(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(objects) != len(entries) {
+				t.Fatalf("got %d objects, want %d", len(objects), len(entries))
+			}
+
+			for i, object := range objects {
+				got := object.Entry
+				got.File = ""
+				if got != entries[i] {
+					t.Errorf("got entry %+v, want %+v", got, entries[i])
+				}
+				if !bytes.Equal(object.Data, data[i]) {
+					t.Errorf("got data %q, want %q", object.Data, data[i])
+				}
+			}
+		})
+	}
+}
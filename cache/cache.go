@@ -0,0 +1,154 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// Object is a cached calendar object
+type Object struct {
+	ETag string `json:"etag"`
+	Data string `json:"data"` // the iCalendar data
+}
+
+// Calendar is the cached copy of a calendar, with the state needed to download only what changed
+type Calendar struct {
+	SyncToken    string            `json:"syncToken,omitempty"`
+	CTag         string            `json:"ctag,omitempty"`
+	LastModified string            `json:"lastModified,omitempty"` // of the calendars downloaded at once, e.g. ICS feeds
+	LastSync     time.Time         `json:"lastSync"`
+	Objects      map[string]Object `json:"objects"` // by path
+
+	file string
+}
+
+// Dir returns the cache directory, under the XDG cache directory
+func Dir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "quickcal"), nil
+}
+
+// Load reads the cached copy of a calendar of a server. A calendar that was never cached is empty
+func Load(server string, calendarPath string) (*Calendar, error) {
+
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	cal := &Calendar{
+		Objects: make(map[string]Object),
+		file:    filepath.Join(dir, url.PathEscape(server), url.PathEscape(strings.Trim(calendarPath, "/"))+".json"),
+	}
+
+	data, err := os.ReadFile(cal.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return cal, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cal); err != nil {
+		return nil, err
+	}
+	if cal.Objects == nil {
+		cal.Objects = make(map[string]Object)
+	}
+
+	return cal, nil
+}
+
+// Save writes the calendar to the cache. The file is replaced atomically, so a failed write doesn't lose the cache
+func (c *Calendar) Save() error {
+
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, c.file)
+}
+
+// Put stores a downloaded calendar object
+func (c *Calendar) Put(object caldav.CalendarObject) error {
+
+	var data strings.Builder
+	if err := ical.NewEncoder(&data).Encode(object.Data); err != nil {
+		return err
+	}
+
+	c.Objects[object.Path] = Object{ETag: object.ETag, Data: data.String()}
+	return nil
+}
+
+// Replace replaces all the cached objects, for calendars that are read at once
+func (c *Calendar) Replace(objects []caldav.CalendarObject) error {
+
+	c.Objects = make(map[string]Object, len(objects))
+	for _, object := range objects {
+		if err := c.Put(object); err != nil {
+			return err
+		}
+	}
+
+	c.LastSync = time.Now()
+	return nil
+}
+
+// CalendarObjects decodes the cached calendar objects, sorted by path
+func (c *Calendar) CalendarObjects() ([]caldav.CalendarObject, error) {
+
+	paths := make([]string, 0, len(c.Objects))
+	for p := range c.Objects {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	objects := make([]caldav.CalendarObject, 0, len(paths))
+	for _, p := range paths {
+		data, err := ical.NewDecoder(strings.NewReader(c.Objects[p].Data)).Decode()
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, caldav.CalendarObject{Path: p, ETag: c.Objects[p].ETag, Data: data})
+	}
+
+	return objects, nil
+}
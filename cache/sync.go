@@ -0,0 +1,117 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cache
+
+import (
+	"time"
+
+	"tsundoku.dev/quickcal/dav"
+)
+
+// Sync downloads the changes of a calendar since the last sync. It uses the sync-collection REPORT when the server
+// supports it, and otherwise compares the ctag of the calendar and the ETags of its objects. Only the new and modified
+// objects are downloaded
+func (c *Calendar) Sync(client *dav.Client, calendarPath string) error {
+
+	result, err := client.SyncCollection(calendarPath, c.SyncToken)
+	if err != nil && c.SyncToken != "" {
+		// the token may have expired, start over
+		c.SyncToken = ""
+		result, err = client.SyncCollection(calendarPath, "")
+	}
+
+	if err == nil && result.Token != "" {
+		if err := c.apply(client, calendarPath, result.Changed, result.Deleted); err != nil {
+			return err
+		}
+
+		c.SyncToken = result.Token
+		c.LastSync = time.Now()
+		return nil
+	}
+
+	return c.syncETags(client, calendarPath)
+}
+
+// syncETags syncs a calendar on servers without sync-collection support
+func (c *Calendar) syncETags(client *dav.Client, calendarPath string) error {
+
+	c.SyncToken = ""
+
+	ctag, _, err := client.CollectionTags(calendarPath)
+	if err != nil {
+		return err
+	}
+	if ctag != "" && ctag == c.CTag {
+		c.LastSync = time.Now()
+		return nil
+	}
+
+	etags, err := client.ETags(calendarPath)
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]string)
+	for p, etag := range etags {
+		if cached, ok := c.Objects[p]; !ok || etag == "" || cached.ETag != etag {
+			changed[p] = etag
+		}
+	}
+
+	deleted := make([]string, 0)
+	for p := range c.Objects {
+		if _, ok := etags[p]; !ok {
+			deleted = append(deleted, p)
+		}
+	}
+
+	if err := c.apply(client, calendarPath, changed, deleted); err != nil {
+		return err
+	}
+
+	c.CTag = ctag
+	c.LastSync = time.Now()
+	return nil
+}
+
+// apply downloads the changed objects and removes the deleted ones
+func (c *Calendar) apply(client *dav.Client, calendarPath string, changed map[string]string, deleted []string) error {
+
+	for _, p := range deleted {
+		delete(c.Objects, p)
+	}
+
+	paths := make([]string, 0, len(changed))
+	for p, etag := range changed {
+		// objects that were uploaded by us may already be up to date
+		if cached, ok := c.Objects[p]; ok && etag != "" && cached.ETag == etag {
+			continue
+		}
+		paths = append(paths, p)
+	}
+
+	objects, err := client.MultiGet(calendarPath, paths)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		if err := c.Put(object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/backup"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	backupCmdFlagCalendars []string
+	backupCmdFlagOutput    string
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Backs up whole calendars",
+	Long: `
+Downloads every object of the tracked calendars, or of the ones given with "calendar", including tasks, journal entries
+and past events. Each object is saved with its path on the server and its ETag, so it can be restored with "restore".
+
+The format of the backup depends on the extension of "output": .zip, .tar, .tar.gz or .tgz. Any other name is used as
+a directory.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't back up in offline mode")
+			return
+		}
+
+		w, err := backup.Create(backupCmdFlagOutput)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		failedCalendars, failedObjects := 0, 0
+		for _, server := range caldavServers {
+			for i := range server.Calendars {
+				calendar := &server.Calendars[i]
+				if !calendarSelected(calendar, backupCmdFlagCalendars) {
+					continue
+				}
+
+				objects, err := server.Backend.Query(calendar.Path, backend.Query{})
+				if err != nil {
+					log.Printf("failed to back up calendar '%s': %v", calendar.Name, err)
+					failedCalendars++
+					continue
+				}
+
+				saved := 0
+				for _, object := range objects {
+					var data bytes.Buffer
+					if err := ical.NewEncoder(&data).Encode(object.Data); err != nil {
+						log.Printf("failed to back up '%s': %v", object.Path, err)
+						failedObjects++
+						continue
+					}
+
+					entry := backup.Entry{
+						Server:       server.Name,
+						Calendar:     calendar.Name,
+						CalendarPath: calendar.Path,
+						Href:         object.Path,
+						ETag:         object.ETag,
+					}
+					if err := w.Add(entry, data.Bytes()); err != nil {
+						log.Println(err)
+						_ = w.Close()
+						return
+					}
+					saved++
+				}
+
+				fmt.Printf("Backed up %d objects of calendar '%s'\n", saved, calendar.Name)
+			}
+		}
+
+		if err := w.Close(); err != nil {
+			log.Println(err)
+			return
+		}
+
+		if failedCalendars > 0 || failedObjects > 0 {
+			log.Printf("the backup is incomplete: %d calendars / %d objects failed", failedCalendars, failedObjects)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+
+	backupCmd.Flags().StringSliceVarP(&backupCmdFlagCalendars, "calendar", "c", nil, "Only back up the calendars with this name or path (it can be used many times)")
+	backupCmd.Flags().StringVarP(&backupCmdFlagOutput, "output", "o", "", "The backup file (.zip, .tar, .tar.gz or .tgz) or directory")
+
+	_ = backupCmd.MarkFlagRequired("output")
+}
+
+// calendarSelected reports whether a calendar has one of the given names or paths. No names selects all calendars
+func calendarSelected(calendar *model.Calendar, names []string) bool {
+
+	if len(names) == 0 {
+		return true
+	}
+
+	for _, name := range names {
+		if calendar.Name == name || calendar.Path == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,166 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/cache"
+	"tsundoku.dev/quickcal/model"
+	"tsundoku.dev/quickcal/queue"
+)
+
+var offline bool
+
+// staleCalendars contains the last sync time of the calendars read from the cache without syncing, by name
+var staleCalendars = make(map[string]time.Time)
+
+// cachedCalendarObjects syncs the local cache of a calendar, and returns all its calendar objects. In offline mode,
+// or when the server is unreachable, the objects of the last sync are returned
+func cachedCalendarObjects(server *model.CalendarServer, calendar *model.Calendar) ([]caldav.CalendarObject, error) {
+
+	cached, err := cache.Load(server.Name, calendar.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if offline {
+		return staleCalendarObjects(server, cached, calendar)
+	}
+
+	if err := server.Backend.Sync(cached, calendar.Path); err != nil {
+		if isUnreachable(err) {
+			log.Printf("server '%s' is unreachable, reading from the cache: %v", server.Name, err)
+			return staleCalendarObjects(server, cached, calendar)
+		}
+		return nil, err
+	}
+
+	// the cache is only an optimization, the synced objects are still valid if it can't be saved
+	if err := cached.Save(); err != nil {
+		log.Printf("failed to save the cache of calendar '%s': %v", calendar.Name, err)
+	}
+
+	return cached.CalendarObjects()
+}
+
+// queryCalendarObjects runs a query against the server of a calendar. In offline mode, or when the server is
+// unreachable, all the cached objects of the calendar are returned instead, and the caller filters them
+func queryCalendarObjects(server *model.CalendarServer, calendar *model.Calendar, query backend.Query) ([]caldav.CalendarObject, error) {
+
+	if !offline {
+		objects, err := server.Backend.Query(calendar.Path, query)
+		if err == nil || !isUnreachable(err) {
+			return objects, err
+		}
+
+		log.Printf("server '%s' is unreachable, reading from the cache: %v", server.Name, err)
+	}
+
+	cached, err := cache.Load(server.Name, calendar.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return staleCalendarObjects(server, cached, calendar)
+}
+
+// staleCalendarObjects returns the cached objects of a calendar that couldn't be synced, with the changes queued for
+// it applied, and remembers it was stale
+func staleCalendarObjects(server *model.CalendarServer, cached *cache.Calendar, calendar *model.Calendar) ([]caldav.CalendarObject, error) {
+
+	staleCalendars[calendar.Name] = cached.LastSync
+
+	objects, err := cached.CalendarObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := queue.Load()
+	if err != nil {
+		log.Printf("failed to read the queued changes: %v", err)
+		return objects, nil
+	}
+
+	return applyQueued(objects, ops, server, calendar), nil
+}
+
+// applyQueued applies the queued changes of a calendar to its objects, so what was created or modified offline is
+// shown. The queued objects have no ETag
+func applyQueued(objects []caldav.CalendarObject, ops []*queue.Operation, server *model.CalendarServer, calendar *model.Calendar) []caldav.CalendarObject {
+
+	for _, op := range ops {
+		if op.Server != server.Name || path.Dir(op.Path)+"/" != calendar.Path {
+			continue
+		}
+
+		// the queued object replaces the cached one, if any
+		kept := objects[:0]
+		for _, object := range objects {
+			if object.Path != op.Path {
+				kept = append(kept, object)
+			}
+		}
+		objects = kept
+
+		if op.Action == queue.ActionDelete {
+			continue
+		}
+
+		cal, err := op.Decode()
+		if err != nil {
+			log.Printf("failed to read the queued %s of '%s': %v", op.Action, op.Path, err)
+			continue
+		}
+		objects = append(objects, caldav.CalendarObject{Path: op.Path, Data: cal})
+	}
+
+	return objects
+}
+
+// isUnreachable reports whether a request failed because the server couldn't be reached, rather than because it
+// answered with an error
+func isUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// warnStale tells which calendars were read from the cache, and when they were last synced
+func warnStale() {
+
+	names := make([]string, 0, len(staleCalendars))
+	for name := range staleCalendars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		lastSync := staleCalendars[name]
+		if lastSync.IsZero() {
+			fmt.Fprintf(os.Stderr, "Offline: calendar '%s' was never synced\n", name)
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "Offline: calendar '%s' is shown as of %s\n", name, lastSync.Format("Mon 02/01/2006 15:04"))
+	}
+}
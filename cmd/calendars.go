@@ -0,0 +1,122 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/config"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/model"
+)
+
+// findCalendar returns the tracked calendar with the given name or path, or the default calendar if name is empty
+func findCalendar(name string) (*model.CalendarServer, *model.Calendar, error) {
+
+	for _, server := range caldavServers {
+		server := server
+		for i := range server.Calendars {
+			calendar := &server.Calendars[i]
+
+			if name == "" && calendar.Default || name != "" && (calendar.Name == name || calendar.Path == name) {
+				return &server, calendar, nil
+			}
+		}
+	}
+
+	if name == "" {
+		return nil, nil, fmt.Errorf("no default calendar found")
+	}
+
+	return nil, nil, fmt.Errorf("calendar '%s' not found", name)
+}
+
+// serverScheduling returns the RFC 6638 scheduling properties of the current user of a server, and the user's email
+// address, either from the configuration file or from the server
+func serverScheduling(server *model.CalendarServer) (*dav.Scheduling, string, error) {
+
+	scheduler, err := serverScheduler(server)
+	if err != nil {
+		return nil, "", err
+	}
+
+	scheduling, err := scheduler.Scheduling()
+	if err != nil {
+		return nil, "", err
+	}
+
+	email := scheduling.MailtoAddress()
+	if cfgServer := config.GetServerByName(&cfg, server.Name); cfgServer != nil && cfgServer.Email != "" {
+		email = cfgServer.Email
+	}
+
+	return scheduling, email, nil
+}
+
+// serverScheduler returns the backend of a server if it supports scheduling
+func serverScheduler(server *model.CalendarServer) (backend.Scheduler, error) {
+
+	scheduler, ok := server.Backend.(backend.Scheduler)
+	if !ok {
+		return nil, fmt.Errorf("server '%s' doesn't support scheduling", server.Name)
+	}
+
+	return scheduler, nil
+}
+
+// userAddresses returns the calendar addresses of the current user on all the servers. Servers that can't be
+// queried only contribute the email of the configuration file
+func userAddresses() []string {
+
+	addresses := make([]string, 0)
+	for _, cfgServer := range cfg.Servers {
+		if cfgServer.Email != "" {
+			addresses = append(addresses, cfgServer.Email)
+		}
+	}
+
+	for _, server := range caldavServers {
+		server := server
+		scheduling, _, err := serverScheduling(&server)
+		if err != nil {
+			continue
+		}
+		addresses = append(addresses, scheduling.Addresses...)
+	}
+
+	return addresses
+}
+
+// newCalendar wraps a component into a VCALENDAR object
+func newCalendar(component *ical.Component) *ical.Calendar {
+
+	calendar := ical.NewCalendar()
+	calendar.Props[ical.PropProductID] = []ical.Prop{
+		{
+			Name:  ical.PropProductID,
+			Value: "-//QuickCal//CalDAV Client//EN",
+		},
+	}
+	calendar.Props[ical.PropVersion] = []ical.Prop{
+		{
+			Name:  ical.PropVersion,
+			Value: "2.0",
+		},
+	}
+	calendar.Children = append(calendar.Children, component)
+
+	return calendar
+}
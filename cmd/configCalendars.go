@@ -17,11 +17,12 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/emersion/go-webdav/caldav"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"tsundoku.dev/quickcal/backend"
 	"tsundoku.dev/quickcal/config"
+	"tsundoku.dev/quickcal/model"
 )
 
 // calendarCmd represents the calendar command
@@ -37,24 +38,19 @@ Scans for calendars on the configured servers and adds them to the config file.
 
 			cfgServer := config.GetServerByName(&cfg, server.Name)
 
-			principal, err := server.Client.FindCurrentUserPrincipal()
-			if err != nil {
-				fmt.Println(err)
-			}
-
-			homeset, err := server.Client.FindCalendarHomeSet(principal)
-			if err != nil {
-				fmt.Println(err)
-			}
-
-			calendars, err := server.Client.FindCalendars(homeset)
+			calendars, err := server.Backend.Calendars()
 			if err != nil {
 				fmt.Println(err)
 			}
 
 			for _, calendar := range calendars {
 
-				err := processCalendar(cfgServer, calendar)
+				serverColor, err := model.NormalizeColor(calendar.Color)
+				if err != nil {
+					serverColor = ""
+				}
+
+				err = processCalendar(cfgServer, calendar, serverColor)
 				if err != nil {
 					fmt.Println(err)
 				}
@@ -75,7 +71,7 @@ func init() {
 	calendarCmd.AddCommand(configCalendarCmd)
 }
 
-func processCalendar(server *config.Server, calendar caldav.Calendar) error {
+func processCalendar(server *config.Server, calendar backend.Calendar, serverColor string) error {
 
 	var cfgCalendar *config.Calendar
 	for _, c := range server.Calendars {
@@ -141,39 +137,12 @@ func processCalendar(server *config.Server, calendar caldav.Calendar) error {
 
 		server.Calendars = append(server.Calendars, cfgCalendar)
 	}
+	cfgCalendar.Components = calendar.Components
 
 	// calendar color
-	availableColors := []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
-	templates := &promptui.SelectTemplates{
-		Label:    `{{.}}`,
-		Active:   "* {{ . }}",
-		Inactive: "{{ . }}",
-	}
-
-	colorPrompt := promptui.Select{
-		Label:     "Pick a color for the calendar",
-		Items:     availableColors,
-		Templates: templates,
-		Size:      8,
-	}
-
-	if cfgCalendar.Color != "" {
-		index := -1
-		for i, c := range availableColors {
-			if c == cfgCalendar.Color {
-				index = i
-				break
-			}
-		}
-
-		if index != -1 {
-			colorPrompt.CursorPos = index
-		}
-	}
-
-	_, selectedColor, err := colorPrompt.Run()
+	selectedColor, err := promptColor(cfgCalendar.Color, serverColor)
 	if err != nil {
-		return fmt.Errorf("prompt failed: %w", err)
+		return err
 	}
 
 	cfgCalendar.Color = selectedColor
@@ -206,3 +175,71 @@ func processCalendar(server *config.Server, calendar caldav.Calendar) error {
 
 	return nil
 }
+
+// promptColor asks for the color of a calendar: the color set on the server, if any, one of the basic colors,
+// or a custom hex or 256-color value
+func promptColor(currentColor string, serverColor string) (string, error) {
+
+	items := make([]string, 0, len(model.ColorNames)+2)
+	if serverColor != "" {
+		items = append(items, fmt.Sprintf("server color (%s)", serverColor))
+	}
+	items = append(items, model.ColorNames...)
+	items = append(items, "custom (hex or 256-color index)")
+
+	templates := &promptui.SelectTemplates{
+		Label:    `{{.}}`,
+		Active:   "* {{ . }}",
+		Inactive: "{{ . }}",
+	}
+
+	colorPrompt := promptui.Select{
+		Label:     "Pick a color for the calendar",
+		Items:     items,
+		Templates: templates,
+		Size:      len(items),
+	}
+
+	// the current color is preselected, new calendars default to the server color
+	switch {
+	case currentColor == "" || currentColor == serverColor:
+		colorPrompt.CursorPos = 0
+	default:
+		colorPrompt.CursorPos = len(items) - 1
+		for i, c := range items {
+			if c == currentColor {
+				colorPrompt.CursorPos = i
+				break
+			}
+		}
+	}
+
+	index, selectedColor, err := colorPrompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+
+	if serverColor != "" && index == 0 {
+		return serverColor, nil
+	}
+
+	if index < len(items)-1 {
+		return selectedColor, nil
+	}
+
+	customPrompt := promptui.Prompt{
+		Label:   "Color (#rrggbb or 0-255)",
+		Default: currentColor,
+		Validate: func(input string) error {
+			_, err := model.NormalizeColor(input)
+			return err
+		},
+	}
+
+	customColor, err := customPrompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+
+	return model.NormalizeColor(customColor)
+}
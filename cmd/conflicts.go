@@ -0,0 +1,140 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"tsundoku.dev/quickcal/model"
+)
+
+// findConflicts returns the busy events overlapping with any of the given time ranges, in the calendars with the given
+// names or paths, or in all of them
+func findConflicts(ranges []interval, calendarNames []string) []*model.CalendarObject {
+
+	conflicts := make([]*model.CalendarObject, 0)
+	if len(ranges) == 0 {
+		return conflicts
+	}
+
+	start, end := ranges[0].start, ranges[0].end
+	for _, r := range ranges[1:] {
+		if r.start.Before(start) {
+			start = r.start
+		}
+		if r.end.After(end) {
+			end = r.end
+		}
+	}
+
+	tz := conflictTimezone()
+
+	// a recurrent event is only reported at its first conflicting occurrence
+	reported := make(map[string]bool)
+	for _, occurrence := range busyOccurrences(start, end, calendarNames, tz) {
+		key := occurrence.Calendar.Path + occurrence.UID
+		if reported[key] {
+			continue
+		}
+
+		busy := eventInterval(occurrence, tz)
+		for _, r := range ranges {
+			if busy.start.Before(r.end) && busy.end.After(r.start) {
+				conflicts = append(conflicts, occurrence)
+				reported[key] = true
+				break
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// busyOccurrences returns the occurrences of the busy events overlapping with the time between from and to, in the
+// calendars with the given names or paths, or in all of them. All-day events are busy whole days in the time zone
+func busyOccurrences(from time.Time, to time.Time, calendarNames []string, tz *time.Location) []*model.CalendarObject {
+
+	// an event that started days before may still be running, so events aren't bounded by the start time here, and
+	// recurrent ones are expanded back by their own duration
+	events := filterCalendars(fetchEvents(time.Time{}, to, false), calendarNames)
+
+	busy := make([]*model.CalendarObject, 0)
+	for _, event := range events {
+		if !event.IsBusy() {
+			continue
+		}
+
+		occurrences := []*model.CalendarObject{event}
+		if event.Recurrence != nil {
+			var err error
+			// the extra day covers all-day events without an end
+			occurrences, err = event.Occurrences(from.Add(-event.Duration()).AddDate(0, 0, -1), to)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+		}
+
+		for _, occurrence := range occurrences {
+			busyTime := eventInterval(occurrence, tz)
+			if busyTime.start.Before(to) && busyTime.end.After(from) {
+				busy = append(busy, occurrence)
+			}
+		}
+	}
+
+	return busy
+}
+
+// conflictTimezone returns the configured time zone, in which all-day events are busy, or the local one
+func conflictTimezone() *time.Location {
+
+	tz, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return time.Local
+	}
+
+	return tz
+}
+
+// checkConflicts warns about the events overlapping with the time ranges of a new event in the target calendar. The
+// conflicts are an error unless they are forced, and always for calendars that reject them
+func checkConflicts(calendarName string, ranges []interval, calendarNames []string, force bool) error {
+
+	_, calendar, err := findCalendar(calendarName)
+	if err != nil {
+		return err
+	}
+
+	conflicts := findConflicts(ranges, calendarNames)
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	log.Println("the event conflicts with:")
+	for _, conflict := range conflicts {
+		log.Println(conflict)
+	}
+
+	if calendar.RejectConflicts {
+		return fmt.Errorf("the calendar '%s' doesn't accept conflicting events", calendar.Name)
+	}
+	if !force {
+		return fmt.Errorf("use --force to create the event anyway")
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/twoway"
+)
+
+var (
+	copyCalendarCmdFlagMirror bool
+	copyCalendarCmdFlagDryRun bool
+)
+
+// copyCalendarCmd represents the calendar copy command
+var copyCalendarCmd = &cobra.Command{
+	Use:   "copy [source] [destination]",
+	Short: "Copies all the objects of a calendar into another one",
+	Long: `
+Copies every object of the source calendar (events, tasks and journal entries) into the destination calendar, by name
+or path. The calendars can be on different servers. The objects keep their UID, and the ones that already exist in the
+destination are left untouched, so the copy can be run again safely.
+
+With "mirror", the destination becomes a copy of the source: the objects that changed are replaced, and the ones that
+no longer exist in the source are deleted. Run it periodically, e.g. from cron, to keep a one-way mirror.
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't copy calendars in offline mode")
+			return
+		}
+
+		srcServer, srcCalendar, err := findCalendar(args[0])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		dstServer, dstCalendar, err := findCalendar(args[1])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if srcServer.Name == dstServer.Name && srcCalendar.Path == dstCalendar.Path {
+			log.Println("the source and destination calendars are the same")
+			return
+		}
+
+		if err := checkWritable(dstCalendar); err != nil {
+			log.Println(err)
+			return
+		}
+
+		srcObjects, err := srcServer.Backend.Query(srcCalendar.Path, backend.Query{})
+		if err != nil {
+			log.Printf("failed to read calendar '%s': %v", srcCalendar.Name, err)
+			return
+		}
+
+		dstObjects, err := dstServer.Backend.Query(dstCalendar.Path, backend.Query{})
+		if err != nil {
+			log.Printf("failed to read calendar '%s': %v", dstCalendar.Name, err)
+			return
+		}
+
+		// the objects are matched by UID, since their paths may differ between servers
+		existing := make(map[string]caldav.CalendarObject)
+		for _, object := range dstObjects {
+			existing[twoway.Key(object)] = object
+		}
+
+		// counts by action, "unchanged" and "failed"
+		counts := make(map[string]int)
+		apply := func(action string, object caldav.CalendarObject, write func() error) {
+			name := path.Base(object.Path)
+			if copyCalendarCmdFlagDryRun {
+				fmt.Printf("Would %s '%s'\n", action, name)
+				counts[action]++
+				return
+			}
+
+			if err := write(); err != nil {
+				log.Printf("failed to %s '%s': %v", action, name, err)
+				counts["failed"]++
+				return
+			}
+			counts[action]++
+		}
+
+		for _, object := range srcObjects {
+			object := object
+			key := twoway.Key(object)
+			dstObject, ok := existing[key]
+			delete(existing, key)
+
+			switch {
+			case !ok:
+				apply("create", object, func() error {
+					_, err := dstServer.Backend.Create(dstCalendar.Path+path.Base(object.Path), object.Data)
+					return err
+				})
+			case !copyCalendarCmdFlagMirror || twoway.Fingerprint(object.Data) == twoway.Fingerprint(dstObject.Data):
+				counts["unchanged"]++
+			default:
+				apply("update", dstObject, func() error {
+					_, err := dstServer.Backend.Update(dstObject.Path, object.Data, dstObject.ETag)
+					return err
+				})
+			}
+		}
+
+		if copyCalendarCmdFlagMirror {
+			for _, object := range existing {
+				object := object
+				apply("delete", object, func() error {
+					return dstServer.Backend.Delete(object.Path, object.ETag)
+				})
+			}
+		}
+
+		fmt.Printf("'%s' to '%s': %d created, %d updated, %d deleted, %d unchanged, %d failed\n", srcCalendar.Name,
+			dstCalendar.Name, counts["create"], counts["update"], counts["delete"], counts["unchanged"], counts["failed"])
+	},
+}
+
+func init() {
+	calendarCmd.AddCommand(copyCalendarCmd)
+
+	copyCalendarCmd.Flags().BoolVar(&copyCalendarCmdFlagMirror, "mirror", false, "Also replace the changed objects and delete the ones missing from the source")
+	copyCalendarCmd.Flags().BoolVar(&copyCalendarCmdFlagDryRun, "dry-run", false, "Only show what would be changed")
+}
@@ -0,0 +1,120 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyEventCmdFlagTo     string
+	copyEventCmdFlagNewUID bool
+	copyEventCmdFlagForce  bool
+)
+
+// copyEventCmd represents the event copy command
+var copyEventCmd = &cobra.Command{
+	Use:   "copy [uid]",
+	Short: "Copies an event to another calendar",
+	Long: `
+Copies the event with the given UID to another calendar, by name or path, keeping all its properties and alarms.
+
+With "new-uid", the copy gets a new UID, so it is a separate event, and its organizer and attendees are removed so they
+aren't invited to it. This is required to copy an event within its own calendar. Copies that conflict with busy events
+of the target calendar, or with any occurrence of a recurrent event during the next year, are refused unless "force"
+is used.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't copy events in offline mode")
+			return
+		}
+
+		event, targetServer, target, err := prepareEventTransfer(args[0], copyEventCmdFlagTo, copyEventCmdFlagForce)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		sameCalendar := event.server.Name == targetServer.Name && event.calendar.Path == target.Path
+		if sameCalendar && !copyEventCmdFlagNewUID {
+			log.Println("an event can only be copied within its calendar with --new-uid")
+			return
+		}
+
+		cal := event.object.Data
+		destination := target.Path + path.Base(event.object.Path)
+		uid := event.event.UID
+		if copyEventCmdFlagNewUID {
+			uid = uuid.NewString()
+			cal = withUID(cal, uid)
+			destination = target.Path + uid + ".ics"
+		}
+
+		if err := copyEventObject(targetServer, destination, cal); err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Printf("Copied '%s' to calendar '%s'\n", event.event.Summary, target.Name)
+		if copyEventCmdFlagNewUID {
+			fmt.Println("UID:", uid)
+		}
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(copyEventCmd)
+
+	copyEventCmd.Flags().StringVar(&copyEventCmdFlagTo, "to", "", "The target calendar, by name or path")
+	copyEventCmd.Flags().BoolVar(&copyEventCmdFlagNewUID, "new-uid", false, "Give the copy a new UID")
+	copyEventCmd.Flags().BoolVarP(&copyEventCmdFlagForce, "force", "f", false, "Copy the event even if it conflicts with other events")
+
+	_ = copyEventCmd.MarkFlagRequired("to")
+}
+
+// withUID returns a copy of the calendar whose components, including overridden occurrences, have the given UID.
+// The organizer and attendees are removed, as the server would otherwise invite them to the copy. Time zones and
+// alarms are kept as they are
+func withUID(cal *ical.Calendar, uid string) *ical.Calendar {
+
+	copied := ical.NewCalendar()
+	for name, props := range cal.Props {
+		copied.Props[name] = props
+	}
+
+	for _, child := range cal.Children {
+		if child.Props.Get(ical.PropUID) != nil {
+			component := &ical.Component{Name: child.Name, Props: make(ical.Props), Children: child.Children}
+			for name, props := range child.Props {
+				component.Props[name] = props
+			}
+			component.Props.SetText(ical.PropUID, uid)
+			component.Props.Del(ical.PropOrganizer)
+			component.Props.Del(ical.PropAttendee)
+			child = component
+		}
+		copied.Children = append(copied.Children, child)
+	}
+
+	return copied
+}
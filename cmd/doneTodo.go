@@ -0,0 +1,111 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+)
+
+var todoDoneCmdFlagRecursive bool
+
+// todoDoneCmd represents the todo done command
+var todoDoneCmd = &cobra.Command{
+	Use:   "done [uid]",
+	Short: "Marks a task as completed",
+	Long: `
+Marks the task with the given UID as completed. The UID is shown by the "todo list" command.
+
+A task with open sub-tasks can only be completed with the "recursive" flag, which completes the sub-tasks as well.
+
+The task is only updated if it hasn't been modified on the server since it was read.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		todo, allTodos, err := findTodo(args[0])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		openDescendants := make([]*todoObject, 0)
+		for _, descendant := range todoDescendants(todo.todo.UID, todoChildren(allTodos)) {
+			if descendant.todo.IsOpen() {
+				openDescendants = append(openDescendants, descendant)
+			}
+		}
+
+		if len(openDescendants) > 0 && !todoDoneCmdFlagRecursive {
+			log.Printf("the task has %d open sub-tasks, use --recursive to complete them as well", len(openDescendants))
+			return
+		}
+
+		// sub-tasks are completed first, so the parent is never completed while a sub-task is still open
+		for i := len(openDescendants) - 1; i >= 0; i-- {
+			if err := completeTodo(openDescendants[i]); err != nil {
+				log.Println(err)
+				return
+			}
+
+			fmt.Println("Completed:", openDescendants[i].todo.Summary)
+		}
+
+		if err := completeTodo(todo); err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Println("Completed:", todo.todo.Summary)
+	},
+}
+
+func init() {
+	todoCmd.AddCommand(todoDoneCmd)
+
+	todoDoneCmd.Flags().BoolVarP(&todoDoneCmdFlagRecursive, "recursive", "r", false, "Complete the open sub-tasks as well")
+}
+
+// completeTodo sets the task as completed, and uploads it if it hasn't changed on the server
+func completeTodo(todo *todoObject) error {
+
+	now := time.Now().UTC()
+	todo.component.Props.SetText(ical.PropStatus, "COMPLETED")
+	todo.component.Props.SetDateTime(ical.PropCompleted, now)
+	todo.component.Props.SetDateTime(ical.PropDateTimeStamp, now)
+	todo.component.Props.SetDateTime(ical.PropLastModified, now)
+	todo.component.Props.Set(&ical.Prop{
+		Name:   ical.PropPercentComplete,
+		Params: ical.Params{},
+		Value:  "100",
+	})
+
+	return updateTodo(todo)
+}
+
+// updateTodo uploads a modified task, if it hasn't changed on the server since it was read
+func updateTodo(todo *todoObject) error {
+
+	_, err := updateObject(todo.server, todo.todo.Calendar, todo.object.Path, todo.object.Data, todo.object.ETag)
+	if backend.IsConflict(err) {
+		return fmt.Errorf("the task was modified on the server, try again")
+	}
+
+	return err
+}
@@ -0,0 +1,130 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/model"
+)
+
+// eventFilter narrows the listed events. Empty fields don't filter
+type eventFilter struct {
+	status       string
+	category     string
+	location     string
+	attendee     string
+	minDuration  time.Duration
+	allDay       bool
+	timed        bool
+	hideDeclined bool
+}
+
+func (f *eventFilter) validate() error {
+	switch strings.ToUpper(f.status) {
+	case "", "CONFIRMED", "TENTATIVE", "CANCELLED":
+		return nil
+	}
+
+	return fmt.Errorf("unknown status '%s', use confirmed, tentative or cancelled", f.status)
+}
+
+// propFilters returns the filters that can be sent to the server. They are also applied locally, since the server
+// matches substrings, and may not support them
+func (f *eventFilter) propFilters() []dav.PropFilter {
+
+	filters := make([]dav.PropFilter, 0)
+	if f.status != "" {
+		filters = append(filters, dav.PropFilter{Name: ical.PropStatus, Text: strings.ToUpper(f.status)})
+	}
+	if f.category != "" {
+		filters = append(filters, dav.PropFilter{Name: ical.PropCategories, Text: f.category})
+	}
+	if f.location != "" {
+		filters = append(filters, dav.PropFilter{Name: ical.PropLocation, Text: f.location})
+	}
+
+	return filters
+}
+
+// apply returns the events matching all the filters
+func (f *eventFilter) apply(events []*model.CalendarObject) []*model.CalendarObject {
+
+	var addresses []string
+	if f.hideDeclined {
+		addresses = userAddresses()
+	}
+
+	filtered := make([]*model.CalendarObject, 0, len(events))
+	for _, event := range events {
+		if f.matches(event, addresses) {
+			filtered = append(filtered, event)
+		}
+	}
+
+	return filtered
+}
+
+// matches reports whether an event matches all the filters. addresses are the user's, to find declined invitations
+func (f *eventFilter) matches(event *model.CalendarObject, addresses []string) bool {
+
+	if f.status != "" && !strings.EqualFold(event.Status, f.status) {
+		return false
+	}
+
+	if f.category != "" {
+		found := false
+		for _, category := range event.Categories {
+			found = found || strings.EqualFold(category, f.category)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.location != "" && !strings.Contains(strings.ToLower(event.Location), strings.ToLower(f.location)) {
+		return false
+	}
+
+	if f.attendee != "" {
+		found := false
+		attendee := strings.ToLower(f.attendee)
+		for _, a := range event.Attendees {
+			found = found || strings.Contains(strings.ToLower(a.Address), attendee) || strings.Contains(strings.ToLower(a.Name), attendee)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.minDuration > 0 && event.Duration() < f.minDuration {
+		return false
+	}
+
+	if f.allDay && !event.AllDay || f.timed && event.AllDay {
+		return false
+	}
+
+	if f.hideDeclined {
+		if attendee := event.Attendee(addresses); attendee != nil && attendee.PartStat == model.PartStatDeclined {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,193 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/model"
+)
+
+// eventObject is an event along with the calendar object that contains it, so it can be updated
+type eventObject struct {
+	event    *model.CalendarObject
+	server   *model.CalendarServer
+	calendar *model.Calendar
+	object   caldav.CalendarObject
+}
+
+// errEventNotFound is returned by findEvent when all the calendars were searched and none has the event
+var errEventNotFound = errors.New("not found")
+
+// findEvent looks for the event with the given UID in all the tracked calendars that support events
+func findEvent(uid string) (*eventObject, error) {
+
+	var searchErr error
+	for _, caldavServer := range caldavServers {
+		caldavServer := caldavServer
+		for i := range caldavServer.Calendars {
+			calendar := &caldavServer.Calendars[i]
+			if !calendar.Supports(ical.CompEvent) {
+				continue
+			}
+
+			// the text match is a substring match, the UID is checked below
+			calendarObjects, err := queryCalendarObjects(&caldavServer, calendar, backend.Query{
+				Component: ical.CompEvent,
+				Filters:   []dav.PropFilter{{Name: ical.PropUID, Text: uid}},
+			})
+			if err != nil {
+				log.Println(err)
+				searchErr = err
+				continue
+			}
+
+			for _, calendarObject := range calendarObjects {
+				event, err := newEventObject(&caldavServer, calendar, calendarObject)
+				if err != nil {
+					return nil, err
+				}
+				if event != nil && event.event.UID == uid {
+					return event, nil
+				}
+			}
+		}
+	}
+
+	// the event may be in a calendar that couldn't be searched
+	if searchErr != nil {
+		return nil, fmt.Errorf("event '%s' not found in the calendars that could be searched: %w", uid, searchErr)
+	}
+
+	return nil, fmt.Errorf("event '%s' %w", uid, errEventNotFound)
+}
+
+// findEvents looks for the events with the given UIDs, reading each tracked calendar once. Events that aren't found
+// are missing from the returned map
+func findEvents(uids []string) map[string]*eventObject {
+
+	wanted := make(map[string]bool, len(uids))
+	for _, uid := range uids {
+		if uid != "" {
+			wanted[uid] = true
+		}
+	}
+
+	found := make(map[string]*eventObject)
+	for _, caldavServer := range caldavServers {
+		caldavServer := caldavServer
+		for i := range caldavServer.Calendars {
+			calendar := &caldavServer.Calendars[i]
+			if !calendar.Supports(ical.CompEvent) || len(found) == len(wanted) {
+				continue
+			}
+
+			calendarObjects, err := cachedCalendarObjects(&caldavServer, calendar)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			for _, calendarObject := range calendarObjects {
+				event, err := newEventObject(&caldavServer, calendar, calendarObject)
+				if err != nil || event == nil || !wanted[event.event.UID] || found[event.event.UID] != nil {
+					continue
+				}
+
+				found[event.event.UID] = event
+			}
+		}
+	}
+
+	return found
+}
+
+// newEventObject reads the event of a calendar object, from its master component when it has overridden occurrences.
+// It returns nil if the object has no event
+func newEventObject(server *model.CalendarServer, calendar *model.Calendar, calendarObject caldav.CalendarObject) (*eventObject, error) {
+
+	e := &eventObject{server: server, calendar: calendar, object: calendarObject}
+
+	component := e.master()
+	if component == nil {
+		events := e.events()
+		if len(events) == 0 {
+			return nil, nil
+		}
+		component = events[0]
+	}
+
+	event, err := model.NewCalendarObject(component, calendar)
+	if err != nil || event == nil {
+		return nil, err
+	}
+	e.event = event
+
+	return e, nil
+}
+
+// events returns the VEVENT components of the object: the event, and the overridden occurrences of recurrent events
+func (e *eventObject) events() []*ical.Component {
+
+	events := make([]*ical.Component, 0, 1)
+	for _, child := range e.object.Data.Children {
+		if child.Name == ical.CompEvent {
+			events = append(events, child)
+		}
+	}
+
+	return events
+}
+
+// master returns the VEVENT without RECURRENCE-ID, which is the event itself, or nil if the object only contains
+// overridden occurrences
+func (e *eventObject) master() *ical.Component {
+
+	for _, event := range e.events() {
+		if event.Props.Get(ical.PropRecurrenceID) == nil {
+			return event
+		}
+	}
+
+	return nil
+}
+
+// override returns the VEVENT overriding the occurrence with the given RECURRENCE-ID, or nil if it isn't overridden
+func (e *eventObject) override(recurrenceID string) *ical.Component {
+
+	for _, event := range e.events() {
+		if prop := event.Props.Get(ical.PropRecurrenceID); prop != nil && prop.Value == recurrenceID {
+			return event
+		}
+	}
+
+	return nil
+}
+
+// update uploads the modified event, if it hasn't changed on the server since it was read
+func (e *eventObject) update() error {
+
+	_, err := updateObject(e.server, e.calendar, e.object.Path, e.object.Data, e.object.ETag)
+	if backend.IsConflict(err) {
+		return fmt.Errorf("the event was modified on the server, try again")
+	}
+
+	return err
+}
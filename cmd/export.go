@@ -0,0 +1,140 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/export"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	exportCmdFlagFormat    string
+	exportCmdFlagOut       string
+	exportCmdFlagFrom      string
+	exportCmdFlagTo        string
+	exportCmdFlagExpand    bool
+	exportCmdFlagCalendars []string
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export events to other calendar tools",
+	Long: `
+Exports the events of the tracked calendars in the native format of other tools, so they can be used without CalDAV support.
+
+The html format writes a static site into the directory given with "out": an agenda page and a page per month,
+using each calendar's color.
+
+Recurrent events are written with the repetition syntax of the target format when it can express their rule, otherwise
+they are expanded between the "from" and "to" dates. Use "expand" to always expand them.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		exporter, ok := export.Exporters[exportCmdFlagFormat]
+		if !ok && exportCmdFlagFormat != "html" {
+			log.Printf("unknown format '%s', available formats: %s", exportCmdFlagFormat, strings.Join(exportFormats(), ", "))
+			return
+		}
+
+		from, to, err := parseTimeRange(exportCmdFlagFrom, exportCmdFlagTo)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		// the html export renders each occurrence in the month pages, and writes a directory instead of a single file
+		if exportCmdFlagFormat == "html" {
+			if exportCmdFlagOut == "" {
+				log.Println("the html format requires an output directory, set with --out")
+				return
+			}
+
+			events := filterCalendars(fetchEvents(from, to, true), exportCmdFlagCalendars)
+			if err := export.HTML(exportCmdFlagOut, events, from, to); err != nil {
+				log.Println(err)
+			}
+			return
+		}
+
+		events := filterCalendars(fetchEvents(from, to, exportCmdFlagExpand), exportCmdFlagCalendars)
+
+		var w io.Writer = os.Stdout
+		if exportCmdFlagOut != "" {
+			file, err := os.Create(exportCmdFlagOut)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			defer file.Close()
+
+			w = file
+		}
+
+		if err := exporter(w, events, from, to); err != nil {
+			log.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&exportCmdFlagFormat, "format", "f", "", fmt.Sprintf("Export format (%s)", strings.Join(exportFormats(), ", ")))
+	exportCmd.Flags().StringVarP(&exportCmdFlagOut, "out", "o", "", "Write the export to this file (or directory, for the html format). Defaults to the standard output")
+	exportCmd.Flags().StringVar(&exportCmdFlagFrom, "from", "", "Export events from this date. Defaults to the current date")
+	exportCmd.Flags().StringVar(&exportCmdFlagTo, "to", "", "Export events to this date. Defaults to 7 days after the from date")
+	exportCmd.Flags().BoolVar(&exportCmdFlagExpand, "expand", false, "Expand recurrent events into single events")
+	exportCmd.Flags().StringSliceVarP(&exportCmdFlagCalendars, "calendar", "c", nil, "Only export the calendars with this name or path (it can be used many times)")
+
+	_ = exportCmd.MarkFlagRequired("format")
+}
+
+func exportFormats() []string {
+	formats := []string{"html"}
+	for format := range export.Exporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// filterCalendars keeps the events of the calendars with the given names or paths. No names means all calendars
+func filterCalendars(events []*model.CalendarObject, names []string) []*model.CalendarObject {
+
+	if len(names) == 0 {
+		return events
+	}
+
+	filtered := make([]*model.CalendarObject, 0, len(events))
+	for _, event := range events {
+		for _, name := range names {
+			if event.Calendar.Name == name || event.Calendar.Path == name {
+				filtered = append(filtered, event)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,317 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	freeCmdFlagDuration     time.Duration
+	freeCmdFlagFrom         string
+	freeCmdFlagTo           string
+	freeCmdFlagCalendars    []string
+	freeCmdFlagBuffer       time.Duration
+	freeCmdFlagWeekends     bool
+	freeCmdFlagBook         string
+	freeCmdFlagBookCalendar string
+)
+
+// interval is a time range, from start (included) to end (excluded)
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// freeCmd represents the free command
+var freeCmd = &cobra.Command{
+	Use:   "free",
+	Short: "Finds free slots across all calendars",
+	Long: `
+Finds the free slots of at least the given duration in the next 7 days, merging the busy time of all the tracked
+calendars, or of the ones given with "calendar". Transparent and cancelled events don't count as busy time.
+
+Only the working hours of the working days are considered. They are set in the configuration file:
+
+availability:
+  workStart: "09:00"
+  workEnd: "17:00"
+  workDays: [mon, tue, wed, thu, fri]
+  buffer: 10m
+
+The buffer is the free time kept before and after every event. With "book", you can pick one of the slots and an event
+with the given summary is created at its start.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if freeCmdFlagDuration <= 0 {
+			log.Println("the duration must be positive, e.g. --duration 45m")
+			return
+		}
+
+		from, to, err := parseTimeRange(freeCmdFlagFrom, freeCmdFlagTo)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		tz, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		workStart, workEnd, workDays, err := workingHours()
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if freeCmdFlagWeekends {
+			workDays[time.Saturday] = true
+			workDays[time.Sunday] = true
+		}
+
+		buffer := cfg.Availability.Buffer
+		if cmd.Flags().Changed("buffer") {
+			buffer = freeCmdFlagBuffer
+		}
+
+		// busy time, extended by the buffer
+		busy := make([]interval, 0)
+		for _, event := range busyOccurrences(from, to, freeCmdFlagCalendars, tz) {
+			busyTime := eventInterval(event, tz)
+			busy = append(busy, interval{start: busyTime.start.Add(-buffer), end: busyTime.end.Add(buffer)})
+		}
+
+		slots := freeSlots(mergeIntervals(busy), from.In(tz), to.In(tz), workStart, workEnd, workDays, freeCmdFlagDuration)
+		if len(slots) == 0 {
+			fmt.Println("No free slots found")
+			return
+		}
+
+		labels := make([]string, 0, len(slots))
+		for _, slot := range slots {
+			labels = append(labels, fmt.Sprintf("%s %s-%s (%s free)", slot.start.Format("Mon 02/01"),
+				slot.start.Format("15:04"), slot.end.Format("15:04"), slot.end.Sub(slot.start)))
+		}
+
+		if freeCmdFlagBook == "" {
+			for _, label := range labels {
+				fmt.Println(label)
+			}
+			return
+		}
+
+		slotPrompt := promptui.Select{
+			Label: "Pick a slot",
+			Items: labels,
+			Size:  10,
+		}
+
+		index, _, err := slotPrompt.Run()
+		if err != nil {
+			log.Printf("prompt failed: %v", err)
+			return
+		}
+
+		start := slots[index].start
+		etag, err := createEvent(freeCmdFlagBookCalendar, newTimedEvent(freeCmdFlagBook, start, start.Add(freeCmdFlagDuration)))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Println(etag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(freeCmd)
+
+	freeCmd.Flags().DurationVarP(&freeCmdFlagDuration, "duration", "d", time.Hour, "Minimum duration of the free slots")
+	freeCmd.Flags().StringVar(&freeCmdFlagFrom, "from", "", "Look for free slots from this date. Defaults to the current date")
+	freeCmd.Flags().StringVar(&freeCmdFlagTo, "to", "", "Look for free slots to this date. Defaults to 7 days after the from date")
+	freeCmd.Flags().StringSliceVarP(&freeCmdFlagCalendars, "calendar", "c", nil, "Only consider the calendars with this name or path (it can be used many times)")
+	freeCmd.Flags().DurationVar(&freeCmdFlagBuffer, "buffer", 0, "Free time to keep before and after every event. Overrides the configuration file")
+	freeCmd.Flags().BoolVar(&freeCmdFlagWeekends, "weekends", false, "Include weekends")
+	freeCmd.Flags().StringVar(&freeCmdFlagBook, "book", "", "Pick a slot and create an event with this summary")
+	freeCmd.Flags().StringVar(&freeCmdFlagBookCalendar, "book-calendar", "", "Calendar for the booked event. Defaults to the default calendar")
+}
+
+// workingHours reads the availability of the configuration file, which defaults to 09:00-17:00 from Monday to Friday.
+// The start and end are returned as offsets from midnight
+func workingHours() (time.Duration, time.Duration, map[time.Weekday]bool, error) {
+
+	parseHour := func(value string, defaultValue time.Duration) (time.Duration, error) {
+		if value == "" {
+			return defaultValue, nil
+		}
+
+		hour, err := time.Parse("15:04", value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid working hour '%s', use hh:mm", value)
+		}
+
+		return time.Duration(hour.Hour())*time.Hour + time.Duration(hour.Minute())*time.Minute, nil
+	}
+
+	workStart, err := parseHour(cfg.Availability.WorkStart, 9*time.Hour)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	workEnd, err := parseHour(cfg.Availability.WorkEnd, 17*time.Hour)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	if workEnd <= workStart {
+		return 0, 0, nil, fmt.Errorf("the working hours must end after they start")
+	}
+
+	workDayNames := cfg.Availability.WorkDays
+	if len(workDayNames) == 0 {
+		workDayNames = []string{"mon", "tue", "wed", "thu", "fri"}
+	}
+
+	workDays := make(map[time.Weekday]bool)
+	for _, name := range workDayNames {
+		found := false
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if strings.EqualFold(name, weekday.String()[:3]) || strings.EqualFold(name, weekday.String()) {
+				workDays[weekday] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return 0, 0, nil, fmt.Errorf("invalid working day '%s'", name)
+		}
+	}
+
+	return workStart, workEnd, workDays, nil
+}
+
+// eventInterval returns the time blocked by an event. All-day events last whole days in the given time zone
+func eventInterval(event *model.CalendarObject, tz *time.Location) interval {
+
+	start, end := *event.Start, *event.Start
+	if event.End != nil {
+		end = *event.End
+	}
+
+	if event.AllDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, tz)
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, tz)
+		if !end.After(start) {
+			end = start.AddDate(0, 0, 1)
+		}
+	}
+
+	return interval{start: start, end: end}
+}
+
+// mergeIntervals sorts the intervals and merges the overlapping ones
+func mergeIntervals(intervals []interval) []interval {
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].start.Before(intervals[j].start)
+	})
+
+	merged := make([]interval, 0, len(intervals))
+	for _, current := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && !current.start.After(merged[last].end) {
+			if current.end.After(merged[last].end) {
+				merged[last].end = current.end
+			}
+			continue
+		}
+
+		merged = append(merged, current)
+	}
+
+	return merged
+}
+
+// timeOfDay returns the wall clock time of a day, so working hours stay the same on days with a DST change
+func timeOfDay(day time.Time, offset time.Duration) time.Time {
+	hour, minute := int(offset/time.Hour), int(offset%time.Hour/time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+// freeSlots returns the gaps between the busy intervals, within the working hours of each working day,
+// that last at least the given duration
+func freeSlots(busy []interval, from time.Time, to time.Time, workStart time.Duration, workEnd time.Duration, workDays map[time.Weekday]bool, duration time.Duration) []interval {
+
+	slots := make([]interval, 0)
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location()); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !workDays[day.Weekday()] {
+			continue
+		}
+
+		windowStart := timeOfDay(day, workStart)
+		windowEnd := timeOfDay(day, workEnd)
+		if windowStart.Before(from) {
+			windowStart = from
+		}
+		if windowEnd.After(to) {
+			windowEnd = to
+		}
+
+		// walk through the busy intervals, the free time is what's left between them
+		cursor := windowStart
+		for _, b := range busy {
+			if !b.end.After(cursor) || !b.start.Before(windowEnd) {
+				continue
+			}
+
+			if b.start.Sub(cursor) >= duration {
+				slots = append(slots, interval{start: cursor, end: b.start})
+			}
+			if b.end.After(cursor) {
+				cursor = b.end
+			}
+		}
+
+		if windowEnd.Sub(cursor) >= duration {
+			slots = append(slots, interval{start: cursor, end: windowEnd})
+		}
+	}
+
+	return slots
+}
+
+// newTimedEvent creates an event between start and end
+func newTimedEvent(summary string, start time.Time, end time.Time) *ical.Component {
+
+	eventComponent := ical.NewComponent(ical.CompEvent)
+	eventComponent.Props.SetText(ical.PropUID, uuid.NewString())
+	eventComponent.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+	eventComponent.Props.SetText(ical.PropSummary, summary)
+	eventComponent.Props.SetDateTime(ical.PropDateTimeStart, start)
+	eventComponent.Props.SetDateTime(ical.PropDateTimeEnd, end)
+
+	return eventComponent
+}
@@ -0,0 +1,59 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+func TestBusyOccurrencesRunning(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	from := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// both events started before the window, and are still running at its start
+	offsite := newTestEvent("offsite", "Offsite", from.AddDate(0, 0, -1))
+	offsite.Props.SetDateTime(ical.PropDateTimeEnd, from.Add(2*time.Hour))
+
+	night := newTestEvent("night", "On call", from.AddDate(0, 0, -7).Add(-2*time.Hour))
+	night.Props.SetDateTime(ical.PropDateTimeEnd, from.AddDate(0, 0, -7).Add(time.Hour))
+	night.Props.Set(&ical.Prop{Name: ical.PropRecurrenceRule, Params: ical.Params{}, Value: "FREQ=DAILY"})
+
+	for path, event := range map[string]*ical.Component{
+		"/personal/offsite.ics": offsite,
+		"/work/night.ics":       night,
+		"/personal/ended.ics":   newTestEvent("ended", "Ended", from.Add(-time.Hour)),
+	} {
+		if _, err := memory.Create(path, newCalendar(event)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	busy := busyOccurrences(from, from.Add(8*time.Hour), nil, time.UTC)
+	got := make(map[string]bool)
+	for _, occurrence := range busy {
+		got[occurrence.Summary] = true
+	}
+	if want := map[string]bool{"Offsite": true, "On call": true}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got busy events %v, want %v", got, want)
+	}
+
+	if got := findConflicts([]interval{{start: from.Add(time.Hour), end: from.Add(90 * time.Minute)}}, nil); len(got) != 1 {
+		t.Fatalf("got conflicts %v, want the offsite", summaries(got))
+	}
+}
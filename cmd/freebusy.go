@@ -0,0 +1,284 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	freeBusyCmdFlagFrom   string
+	freeBusyCmdFlagTo     string
+	freeBusyCmdFlagServer string
+)
+
+// freeBusyRow is the busy time of a calendar or of an attendee
+type freeBusyRow struct {
+	label   string
+	periods []model.BusyPeriod
+}
+
+// freeBusyCmd represents the freebusy command
+var freeBusyCmd = &cobra.Command{
+	Use:   "freebusy [calendar|attendee...]",
+	Short: "Shows the busy time of calendars and people",
+	Long: `
+Shows a timeline of the busy time of the given calendars and attendees, in the next 7 days. Without arguments, the busy
+time of every tracked calendar is shown. The flags "from" and "to" can be used to override the time range.
+
+Calendars, by name or path, are queried with a free-busy-query. Attendees, by email address, are queried through the
+scheduling outbox of the default calendar's server (or the one given with "server"), which must support RFC 6638.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		from, to, err := parseTimeRange(freeBusyCmdFlagFrom, freeBusyCmdFlagTo)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		tz, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		var calendarNames, attendees []string
+		for _, arg := range args {
+			if strings.Contains(arg, "@") {
+				attendees = append(attendees, strings.TrimPrefix(strings.ToLower(arg), "mailto:"))
+			} else {
+				calendarNames = append(calendarNames, arg)
+			}
+		}
+
+		if len(args) == 0 {
+			for _, server := range caldavServers {
+				for _, calendar := range server.Calendars {
+					calendarNames = append(calendarNames, calendar.Path)
+				}
+			}
+		}
+
+		rows := make([]freeBusyRow, 0, len(args))
+		for _, name := range calendarNames {
+			server, calendar, err := findCalendar(name)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			// without a server to ask, the busy time is computed from the events
+			scheduler, ok := server.Backend.(backend.Scheduler)
+			if !ok {
+				rows = append(rows, freeBusyRow{label: calendar.Name, periods: eventBusyPeriods(calendar, from, to, tz)})
+				continue
+			}
+
+			freeBusy, err := scheduler.FreeBusy(calendar.Path, from, to)
+			if err != nil {
+				log.Printf("Failed to query calendar '%s': %v", calendar.Name, err)
+				continue
+			}
+
+			periods, err := model.NewBusyPeriods(freeBusy)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			rows = append(rows, freeBusyRow{label: calendar.Name, periods: periods})
+		}
+
+		if len(attendees) > 0 {
+			attendeeRows, err := queryAttendeesFreeBusy(attendees, from, to)
+			if err != nil {
+				log.Println(err)
+			}
+
+			rows = append(rows, attendeeRows...)
+		}
+
+		for _, row := range rows {
+			printTimeline(row, from, to, tz)
+		}
+
+		fmt.Println("█ busy  ▓ unavailable  ▒ tentative  · free")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(freeBusyCmd)
+
+	freeBusyCmd.Flags().StringVar(&freeBusyCmdFlagFrom, "from", "", "Show the busy time from this date. Defaults to the current date")
+	freeBusyCmd.Flags().StringVar(&freeBusyCmdFlagTo, "to", "", "Show the busy time to this date. Defaults to 7 days after the from date")
+	freeBusyCmd.Flags().StringVar(&freeBusyCmdFlagServer, "server", "", "Server used to query the attendees. Defaults to the server of the default calendar")
+}
+
+// queryAttendeesFreeBusy sends a VFREEBUSY request to the scheduling outbox, which answers for every attendee
+func queryAttendeesFreeBusy(attendees []string, from time.Time, to time.Time) ([]freeBusyRow, error) {
+
+	var server *model.CalendarServer
+	if freeBusyCmdFlagServer != "" {
+		caldavServer, ok := caldavServers[freeBusyCmdFlagServer]
+		if !ok {
+			return nil, fmt.Errorf("server '%s' not found", freeBusyCmdFlagServer)
+		}
+		server = &caldavServer
+	} else {
+		var err error
+		server, _, err = findCalendar("")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scheduler, err := serverScheduler(server)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduling, email, err := serverScheduling(server)
+	if err != nil {
+		return nil, err
+	}
+	if scheduling.OutboxURL == "" {
+		return nil, fmt.Errorf("server '%s' doesn't support scheduling, attendees can't be queried", server.Name)
+	}
+	if email == "" {
+		return nil, fmt.Errorf("no email address found for server '%s', set it in the configuration file", server.Name)
+	}
+
+	freeBusyComponent := ical.NewComponent(ical.CompFreeBusy)
+	freeBusyComponent.Props.SetText(ical.PropUID, uuid.NewString())
+	freeBusyComponent.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+	freeBusyComponent.Props.SetDateTime(ical.PropDateTimeStart, from.UTC())
+	freeBusyComponent.Props.SetDateTime(ical.PropDateTimeEnd, to.UTC())
+	freeBusyComponent.Props.Set(&ical.Prop{Name: ical.PropOrganizer, Params: ical.Params{}, Value: "mailto:" + email})
+	for _, attendee := range attendees {
+		freeBusyComponent.Props.Add(&ical.Prop{Name: ical.PropAttendee, Params: ical.Params{}, Value: "mailto:" + attendee})
+	}
+
+	request := newCalendar(freeBusyComponent)
+	request.Props.SetText(ical.PropMethod, "REQUEST")
+
+	responses, err := scheduler.PostOutbox(scheduling.OutboxURL, request)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]freeBusyRow, 0, len(responses))
+	for _, response := range responses {
+		label := strings.TrimPrefix(strings.ToLower(response.Recipient), "mailto:")
+
+		// request statuses starting with 2 are successful
+		if response.Data == nil || !strings.HasPrefix(response.RequestStatus, "2") {
+			log.Printf("No free/busy information for '%s': %s", label, response.RequestStatus)
+			continue
+		}
+
+		periods, err := model.NewBusyPeriods(response.Data)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		rows = append(rows, freeBusyRow{label: label, periods: periods})
+	}
+
+	return rows, nil
+}
+
+// eventBusyPeriods returns the busy time of the events of a calendar between from and to. All-day events last whole
+// days in the given time zone
+func eventBusyPeriods(calendar *model.Calendar, from time.Time, to time.Time, tz *time.Location) []model.BusyPeriod {
+
+	periods := make([]model.BusyPeriod, 0)
+	for _, event := range busyOccurrences(from, to, []string{calendar.Path}, tz) {
+		// events running at the start or the end of the range are cut
+		busyTime := eventInterval(event, tz)
+		if busyTime.start.Before(from) {
+			busyTime.start = from
+		}
+		if busyTime.end.After(to) {
+			busyTime.end = to
+		}
+
+		periodType := model.FreeBusyBusy
+		if event.Status == "TENTATIVE" {
+			periodType = model.FreeBusyBusyTentative
+		}
+		periods = append(periods, model.BusyPeriod{Start: busyTime.start, End: busyTime.end, Type: periodType})
+	}
+
+	return periods
+}
+
+// printTimeline prints a line per day with the busy time of a row, in slots of 30 minutes
+func printTimeline(row freeBusyRow, from time.Time, to time.Time, tz *time.Location) {
+
+	const slot = 30 * time.Minute
+
+	fmt.Println(row.label)
+
+	ruler := strings.Builder{}
+	ruler.WriteString(strings.Repeat(" ", 12))
+	for hour := 0; hour < 24; hour += 3 {
+		ruler.WriteString(fmt.Sprintf("%-6s", fmt.Sprintf("%02d", hour)))
+	}
+	fmt.Println(ruler.String())
+
+	from = from.In(tz)
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, tz); day.Before(to); day = day.AddDate(0, 0, 1) {
+
+		line := strings.Builder{}
+		line.WriteString(fmt.Sprintf("%-12s", day.Format("Mon 02/01")))
+
+		for slotStart := day; slotStart.Before(day.AddDate(0, 0, 1)); slotStart = slotStart.Add(slot) {
+			slotEnd := slotStart.Add(slot)
+
+			// the most restrictive type wins when periods overlap
+			symbol := "·"
+			for _, period := range row.periods {
+				if !period.Start.Before(slotEnd) || !period.End.After(slotStart) {
+					continue
+				}
+
+				switch {
+				case period.Type == model.FreeBusyBusy:
+					symbol = "█"
+				case period.Type == model.FreeBusyBusyUnavailable && symbol != "█":
+					symbol = "▓"
+				case symbol == "·":
+					symbol = "▒"
+				}
+			}
+
+			line.WriteString(symbol)
+		}
+
+		fmt.Println(line.String())
+	}
+
+	fmt.Println()
+}
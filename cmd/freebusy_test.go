@@ -0,0 +1,47 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/model"
+)
+
+func TestEventBusyPeriodsRunning(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	// the trip started the day before and ends after the range
+	trip := newTestEvent("trip", "Trip", from.Add(-12*time.Hour))
+	trip.Props.SetDateTime(ical.PropDateTimeEnd, from.AddDate(0, 0, 2))
+	trip.Props.SetText(ical.PropStatus, "TENTATIVE")
+	if _, err := memory.Create("/personal/trip.ics", newCalendar(trip)); err != nil {
+		t.Fatal(err)
+	}
+
+	periods := eventBusyPeriods(&caldavServers["memory"].Calendars[0], from, from.AddDate(0, 0, 1), time.UTC)
+	if len(periods) != 1 {
+		t.Fatalf("got periods %v, want the trip", periods)
+	}
+	if !periods[0].Start.Equal(from) || !periods[0].End.Equal(from.AddDate(0, 0, 1)) {
+		t.Errorf("got period %v - %v, want the whole range", periods[0].Start, periods[0].End)
+	}
+	if periods[0].Type != model.FreeBusyBusyTentative {
+		t.Errorf("got type %q", periods[0].Type)
+	}
+}
@@ -0,0 +1,83 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/backend/backendtest"
+	"tsundoku.dev/quickcal/config"
+	"tsundoku.dev/quickcal/model"
+)
+
+// setupMemoryServer tracks a single in-memory server, with the default calendar "Personal" and the calendar "Work".
+// The cache and the queue are kept in temporary directories
+func setupMemoryServer(t *testing.T) *backendtest.Memory {
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	previousServers, previousCfg, previousOffline := caldavServers, cfg, offline
+	t.Cleanup(func() {
+		caldavServers, cfg, offline = previousServers, previousCfg, previousOffline
+		staleCalendars = make(map[string]time.Time)
+	})
+
+	memory := backendtest.NewMemory(
+		backend.Calendar{Path: "/personal/", Name: "Personal"},
+		backend.Calendar{Path: "/work/", Name: "Work"},
+	)
+
+	caldavServers = map[string]model.CalendarServer{
+		"memory": {
+			Name:    "memory",
+			Backend: memory,
+			Calendars: []model.Calendar{
+				{Name: "Personal", Path: "/personal/", Default: true},
+				{Name: "Work", Path: "/work/"},
+			},
+		},
+	}
+	cfg = config.Config{Timezone: "Europe/Madrid"}
+	offline = false
+
+	return memory
+}
+
+// newTestEvent returns a VEVENT lasting an hour
+func newTestEvent(uid string, summary string, start time.Time) *ical.Component {
+
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, uid)
+	event.Props.SetText(ical.PropSummary, summary)
+	event.Props.SetDateTime(ical.PropDateTimeStamp, start)
+	event.Props.SetDateTime(ical.PropDateTimeStart, start)
+	event.Props.SetDateTime(ical.PropDateTimeEnd, start.Add(time.Hour))
+
+	return event.Component
+}
+
+// summaries returns the summaries of the events, in order
+func summaries(events []*model.CalendarObject) []string {
+
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		result = append(result, event.Summary)
+	}
+
+	return result
+}
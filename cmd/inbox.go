@@ -0,0 +1,245 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+)
+
+// inboxMessage is an iTIP message of a scheduling inbox
+type inboxMessage struct {
+	id        string // the name of the message in the inbox
+	server    *model.CalendarServer
+	object    caldav.CalendarObject
+	method    string
+	component *ical.Component // the first event or task of the message
+	event     *model.CalendarObject
+}
+
+// inboxCmd represents the inbox command
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Lists the messages of the scheduling inbox",
+	Long: `
+Lists the iTIP messages, such as invitations, cancellations, replies and counter-proposals, that the servers put in
+your scheduling inbox (RFC 6638), along with what they would change in your calendars.
+
+Apply them with "inbox apply [id]", or discard them with "inbox discard [id]".
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		messages := fetchInboxMessages()
+		if len(messages) == 0 {
+			fmt.Println("The inbox is empty")
+			return
+		}
+
+		uids := make([]string, 0, len(messages))
+		for _, message := range messages {
+			uids = append(uids, message.uid())
+		}
+		events := findEvents(uids)
+
+		for _, message := range messages {
+			fmt.Printf("%s\t%s\t%s\n", message.id, message.method, message.describe(events[message.uid()]))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inboxCmd)
+}
+
+// fetchInboxMessages returns the messages of the scheduling inboxes of all the servers
+func fetchInboxMessages() []*inboxMessage {
+
+	messages := make([]*inboxMessage, 0)
+	for _, caldavServer := range caldavServers {
+		caldavServer := caldavServer
+		if _, ok := caldavServer.Backend.(backend.Scheduler); !ok {
+			continue
+		}
+
+		scheduling, _, err := serverScheduling(&caldavServer)
+		if err != nil {
+			log.Printf("failed to find the scheduling inbox of server '%s': %v", caldavServer.Name, err)
+			continue
+		}
+		if scheduling.InboxURL == "" {
+			continue
+		}
+
+		objects, err := caldavServer.Backend.Query(scheduling.InboxURL, backend.Query{}) // every message
+		if err != nil {
+			log.Printf("failed to read the scheduling inbox of server '%s': %v", caldavServer.Name, err)
+			continue
+		}
+
+		for _, object := range objects {
+			message := &inboxMessage{
+				id:     strings.TrimSuffix(path.Base(object.Path), ".ics"),
+				server: &caldavServer,
+				object: object,
+				method: strings.ToUpper(model.PropValue(object.Data.Component, ical.PropMethod)),
+			}
+
+			for _, child := range object.Data.Children {
+				if child.Name == ical.CompEvent || child.Name == ical.CompToDo {
+					message.component = child
+					break
+				}
+			}
+			if message.component != nil && message.component.Name == ical.CompEvent {
+				message.event, _ = model.NewCalendarObject(message.component, nil)
+			}
+
+			messages = append(messages, message)
+		}
+	}
+
+	return messages
+}
+
+// findInboxMessage returns the message with the given id
+func findInboxMessage(messages []*inboxMessage, id string) (*inboxMessage, error) {
+	for _, message := range messages {
+		if message.id == id {
+			return message, nil
+		}
+	}
+
+	return nil, fmt.Errorf("message '%s' not found", id)
+}
+
+// remove deletes the message from the inbox. When the server is unreachable, the deletion is queued
+func (m *inboxMessage) remove() error {
+	inbox := &model.Calendar{Name: "scheduling inbox", Path: path.Dir(m.object.Path) + "/"}
+	return deleteObject(m.server, inbox, m.object.Path, m.object.ETag)
+}
+
+func (m *inboxMessage) uid() string {
+	if m.component == nil {
+		return ""
+	}
+
+	return model.PropValue(m.component, ical.PropUID)
+}
+
+func (m *inboxMessage) summary() string {
+	if m.component == nil {
+		return ""
+	}
+
+	return model.PropValue(m.component, ical.PropSummary)
+}
+
+// sender returns the organizer of requests and cancellations, or the attendee of replies and counter-proposals
+func (m *inboxMessage) sender() string {
+	if m.component == nil {
+		return ""
+	}
+
+	if m.method == "REPLY" || m.method == "COUNTER" {
+		if attendee := m.component.Props.Get(ical.PropAttendee); attendee != nil {
+			return model.NewAttendee(*attendee).String()
+		}
+	}
+	if organizer := m.component.Props.Get(ical.PropOrganizer); organizer != nil {
+		return model.NewAttendee(*organizer).String()
+	}
+
+	return ""
+}
+
+// describe tells what applying the message would change in the existing event, which is nil if it's not in any calendar
+func (m *inboxMessage) describe(existing *eventObject) string {
+
+	if m.event == nil {
+		return fmt.Sprintf("unsupported message from %s: %s", m.sender(), m.summary())
+	}
+
+	switch m.method {
+	case "REQUEST":
+		if existing == nil {
+			return fmt.Sprintf("new invitation from %s: %s on %s", m.sender(), m.summary(), formatEventTime(m.event))
+		}
+
+		return fmt.Sprintf("update from %s of %s: %s", m.sender(), existing.event.Summary, eventChanges(existing.event, m.event))
+	case "CANCEL":
+		if existing == nil {
+			return fmt.Sprintf("cancellation from %s of %s, which is not in your calendars", m.sender(), m.summary())
+		}
+
+		return fmt.Sprintf("cancellation from %s of %s on %s", m.sender(), existing.event.Summary, formatEventTime(existing.event))
+	case "REPLY":
+		partStat := ""
+		if attendee := m.component.Props.Get(ical.PropAttendee); attendee != nil {
+			partStat = strings.ToLower(model.NewAttendee(*attendee).PartStat)
+		}
+
+		return fmt.Sprintf("%s replied %s to %s", m.sender(), partStat, m.summary())
+	case "COUNTER":
+		return fmt.Sprintf("%s proposes to move %s to %s", m.sender(), m.summary(), formatEventTime(m.event))
+	}
+
+	return fmt.Sprintf("unsupported %s message from %s: %s", m.method, m.sender(), m.summary())
+}
+
+// formatEventTime returns the start of an event, as shown in listings
+func formatEventTime(event *model.CalendarObject) string {
+	if event.AllDay {
+		return event.Start.Format("Mon 02/01/2006")
+	}
+
+	return event.Start.Format("Mon 02/01/2006 15:04")
+}
+
+// eventChanges describes the differences between two versions of an event
+func eventChanges(old *model.CalendarObject, new *model.CalendarObject) string {
+
+	changes := make([]string, 0)
+	if !old.Start.Equal(*new.Start) {
+		changes = append(changes, fmt.Sprintf("time %s -> %s", formatEventTime(old), formatEventTime(new)))
+	}
+	if old.Duration() != new.Duration() {
+		changes = append(changes, fmt.Sprintf("duration %s -> %s", old.Duration(), new.Duration()))
+	}
+	if old.Summary != new.Summary {
+		changes = append(changes, fmt.Sprintf("summary '%s' -> '%s'", old.Summary, new.Summary))
+	}
+	if old.Location != new.Location {
+		changes = append(changes, fmt.Sprintf("location '%s' -> '%s'", old.Location, new.Location))
+	}
+	if old.Status != new.Status {
+		changes = append(changes, fmt.Sprintf("status %s -> %s", old.Status, new.Status))
+	}
+	if len(old.Attendees) != len(new.Attendees) {
+		changes = append(changes, fmt.Sprintf("%d -> %d attendees", len(old.Attendees), len(new.Attendees)))
+	}
+
+	if len(changes) == 0 {
+		return "no visible changes"
+	}
+
+	return strings.Join(changes, ", ")
+}
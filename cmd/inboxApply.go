@@ -0,0 +1,341 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	inboxCmdFlagAll      bool
+	inboxCmdFlagCalendar string
+)
+
+// inboxApplyCmd represents the inbox apply command
+var inboxApplyCmd = &cobra.Command{
+	Use:   "apply [id...]",
+	Short: "Applies messages of the scheduling inbox to your calendars",
+	Long: `
+Applies the messages with the given ids, or all of them with "all", and removes them from the inbox:
+
+- invitations are added to the default calendar, or the one given with "calendar", and updates replace the event, or
+  the occurrence they refer to, keeping your participation status and alarms
+- cancellations mark the event, or the cancelled occurrence, as cancelled
+- replies update the participation status of the attendee in the event you organize
+- counter-proposals move the event you organize to the proposed time
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		messages, err := selectInboxMessages(args)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for _, message := range messages {
+			if err := applyInboxMessage(message); err != nil {
+				log.Printf("failed to apply message '%s': %v", message.id, err)
+				continue
+			}
+
+			if err := message.remove(); err != nil {
+				log.Printf("failed to remove message '%s' from the inbox: %v", message.id, err)
+				continue
+			}
+
+			fmt.Printf("Applied %s: %s\n", message.method, message.summary())
+		}
+	},
+}
+
+// inboxDiscardCmd represents the inbox discard command
+var inboxDiscardCmd = &cobra.Command{
+	Use:   "discard [id...]",
+	Short: "Removes messages from the scheduling inbox",
+	Long: `
+Removes the messages with the given ids, or all of them with "all", from the inbox without applying them.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		messages, err := selectInboxMessages(args)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for _, message := range messages {
+			if err := message.remove(); err != nil {
+				log.Printf("failed to remove message '%s' from the inbox: %v", message.id, err)
+				continue
+			}
+
+			fmt.Printf("Discarded %s: %s\n", message.method, message.summary())
+		}
+	},
+}
+
+func init() {
+	inboxCmd.AddCommand(inboxApplyCmd)
+	inboxCmd.AddCommand(inboxDiscardCmd)
+
+	inboxApplyCmd.Flags().BoolVarP(&inboxCmdFlagAll, "all", "a", false, "Apply all the messages")
+	inboxApplyCmd.Flags().StringVarP(&inboxCmdFlagCalendar, "calendar", "c", "", "Calendar for new invitations. Defaults to the default calendar")
+	inboxDiscardCmd.Flags().BoolVarP(&inboxCmdFlagAll, "all", "a", false, "Discard all the messages")
+}
+
+// selectInboxMessages returns the messages with the given ids, or all of them with the "all" flag
+func selectInboxMessages(ids []string) ([]*inboxMessage, error) {
+
+	if len(ids) == 0 && !inboxCmdFlagAll {
+		return nil, fmt.Errorf("give the ids of the messages, or use --all")
+	}
+
+	messages := fetchInboxMessages()
+	if inboxCmdFlagAll {
+		return messages, nil
+	}
+
+	selected := make([]*inboxMessage, 0, len(ids))
+	for _, id := range ids {
+		message, err := findInboxMessage(messages, id)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, message)
+	}
+
+	return selected, nil
+}
+
+// applyInboxMessage applies a message to the event it refers to
+func applyInboxMessage(message *inboxMessage) error {
+
+	if message.event == nil {
+		return fmt.Errorf("only event messages are supported")
+	}
+
+	existing, err := findEvent(message.uid())
+	if errors.Is(err, errEventNotFound) {
+		switch message.method {
+		case "REQUEST":
+			server, calendar, err := findCalendar(inboxCmdFlagCalendar)
+			if err != nil {
+				return err
+			}
+
+			_, err = createObject(server, calendar, calendar.Path+message.uid()+".ics", storedCalendar(message.object.Data))
+			return err
+		case "CANCEL":
+			// a cancellation of an event that isn't in the calendars has nothing to cancel
+			return nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	switch message.method {
+	case "REQUEST":
+		mergeRequest(existing, message.object.Data)
+	case "CANCEL":
+		recurrenceID := message.component.Props.Get(ical.PropRecurrenceID)
+		cancelled := false
+		for _, component := range existing.events() {
+			componentRecurrenceID := component.Props.Get(ical.PropRecurrenceID)
+			if recurrenceID != nil && (componentRecurrenceID == nil || componentRecurrenceID.Value != recurrenceID.Value) {
+				continue
+			}
+
+			component.Props.SetText(ical.PropStatus, "CANCELLED")
+			component.Props.SetDateTime(ical.PropDateTimeStamp, now)
+			cancelled = true
+		}
+
+		// a single cancelled occurrence without an override is excluded from the recurrence
+		if !cancelled && recurrenceID != nil {
+			master := existing.master()
+			if master == nil {
+				return fmt.Errorf("the event has no recurrence to exclude the occurrence from")
+			}
+			exdate := *recurrenceID
+			exdate.Name = ical.PropExceptionDates
+			master.Props.Add(&exdate)
+		}
+	case "REPLY":
+		for _, replyAttendee := range message.component.Props.Values(ical.PropAttendee) {
+			partStat := model.NewAttendee(replyAttendee).PartStat
+			for _, component := range existing.events() {
+				if attendee := findAttendeeProp(component, []string{replyAttendee.Value}); attendee != nil {
+					attendee.Params.Set(ical.ParamParticipationStatus, partStat)
+				}
+			}
+		}
+	case "COUNTER":
+		target, err := counteredComponent(existing, message.component.Props.Get(ical.PropRecurrenceID))
+		if err != nil {
+			return err
+		}
+
+		for _, name := range []string{ical.PropDateTimeStart, ical.PropDateTimeEnd, ical.PropDuration} {
+			target.Props.Del(name)
+			if prop := message.component.Props.Get(name); prop != nil {
+				target.Props.Set(prop)
+			}
+		}
+
+		// attendees are notified of the new time because the sequence increases
+		sequence, _ := strconv.Atoi(model.PropValue(target, ical.PropSequence))
+		target.Props.Set(&ical.Prop{Name: ical.PropSequence, Params: ical.Params{}, Value: strconv.Itoa(sequence + 1)})
+		target.Props.SetDateTime(ical.PropDateTimeStamp, now)
+		target.Props.SetDateTime(ical.PropLastModified, now)
+	default:
+		return fmt.Errorf("unsupported %s message", message.method)
+	}
+
+	return existing.update()
+}
+
+// counteredComponent returns the component a counter-proposal moves: the event, or the occurrence it refers to. An
+// occurrence without an override gets one, copied from the event without its recurrence
+func counteredComponent(existing *eventObject, recurrenceID *ical.Prop) (*ical.Component, error) {
+
+	if recurrenceID != nil {
+		if override := existing.override(recurrenceID.Value); override != nil {
+			return override, nil
+		}
+	}
+
+	master := existing.master()
+	if master == nil {
+		return nil, fmt.Errorf("the proposed occurrence is not in the event")
+	}
+	if recurrenceID == nil {
+		return master, nil
+	}
+
+	override := ical.NewComponent(ical.CompEvent)
+	for name, props := range master.Props {
+		switch name {
+		case ical.PropRecurrenceRule, ical.PropRecurrenceDates, ical.PropExceptionDates:
+		default:
+			override.Props[name] = append([]ical.Prop(nil), props...)
+		}
+	}
+	override.Props.Set(recurrenceID)
+	override.Children = master.Children
+	existing.object.Data.Children = append(existing.object.Data.Children, override)
+
+	return override, nil
+}
+
+// mergeRequest applies an updated invitation to the event: each event of the request replaces the occurrence with the
+// same RECURRENCE-ID, or the event itself, and the other occurrences are kept. The participation status of the user
+// and the alarms are local, so they are kept too
+func mergeRequest(existing *eventObject, request *ical.Calendar) {
+
+	addresses := userAddresses()
+	for _, child := range request.Children {
+		if child.Name != ical.CompEvent {
+			if child.Name == ical.CompTimezone && !hasTimezone(existing.object.Data, model.PropValue(child, ical.PropTimezoneID)) {
+				existing.object.Data.Children = append(existing.object.Data.Children, child)
+			}
+			continue
+		}
+
+		var current *ical.Component
+		if recurrenceID := child.Props.Get(ical.PropRecurrenceID); recurrenceID != nil {
+			current = existing.override(recurrenceID.Value)
+		} else {
+			current = existing.master()
+		}
+
+		// a new override keeps what the user set for the whole event
+		local := current
+		if local == nil {
+			local = existing.master()
+		}
+		if local != nil {
+			keepLocalState(child, local, addresses)
+		}
+
+		if current == nil {
+			existing.object.Data.Children = append(existing.object.Data.Children, child)
+			continue
+		}
+		for i, component := range existing.object.Data.Children {
+			if component == current {
+				existing.object.Data.Children[i] = child
+			}
+		}
+	}
+}
+
+// keepLocalState copies the participation status of the user and the alarms of the stored event to its new version
+func keepLocalState(updated *ical.Component, stored *ical.Component, addresses []string) {
+
+	if attendee := findAttendeeProp(stored, addresses); attendee != nil {
+		partStat := attendee.Params.Get(ical.ParamParticipationStatus)
+		if updatedAttendee := findAttendeeProp(updated, []string{attendee.Value}); updatedAttendee != nil && partStat != "" {
+			updatedAttendee.Params.Set(ical.ParamParticipationStatus, partStat)
+		}
+	}
+
+	children := make([]*ical.Component, 0, len(updated.Children))
+	for _, child := range updated.Children {
+		if child.Name != ical.CompAlarm {
+			children = append(children, child)
+		}
+	}
+	for _, child := range stored.Children {
+		if child.Name == ical.CompAlarm {
+			children = append(children, child)
+		}
+	}
+	updated.Children = children
+}
+
+// hasTimezone returns whether the calendar defines the VTIMEZONE with the given TZID
+func hasTimezone(cal *ical.Calendar, tzid string) bool {
+
+	for _, child := range cal.Children {
+		if child.Name == ical.CompTimezone && model.PropValue(child, ical.PropTimezoneID) == tzid {
+			return true
+		}
+	}
+
+	return false
+}
+
+// storedCalendar returns a copy of an iTIP message that can be stored in a calendar, which can't have a method
+func storedCalendar(message *ical.Calendar) *ical.Calendar {
+
+	cal := ical.NewCalendar()
+	for name, props := range message.Props {
+		if name != ical.PropMethod {
+			cal.Props[name] = props
+		}
+	}
+	cal.Children = message.Children
+
+	return cal
+}
@@ -0,0 +1,208 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/backend/backendtest"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/model"
+)
+
+const testAddress = "mailto:me@example.com"
+
+var weeklyStart = time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+
+// setupInbox adds a weekly event, which the user accepted and has an alarm for, with its second occurrence moved
+func setupInbox(t *testing.T) *backendtest.Memory {
+
+	memory := setupMemoryServer(t)
+	memory.SetScheduling(&dav.Scheduling{InboxURL: "/inbox/", Addresses: []string{testAddress}})
+
+	master := newTestEvent("weekly", "Sync", weeklyStart)
+	master.Props.Set(&ical.Prop{Name: ical.PropRecurrenceRule, Params: ical.Params{}, Value: "FREQ=WEEKLY"})
+	master.Props.Add(&ical.Prop{Name: ical.PropAttendee, Params: ical.Params{ical.ParamParticipationStatus: {"ACCEPTED"}}, Value: testAddress})
+	alarm := ical.NewComponent(ical.CompAlarm)
+	alarm.Props.SetText(ical.PropAction, "DISPLAY")
+	alarm.Props.Set(&ical.Prop{Name: ical.PropTrigger, Params: ical.Params{}, Value: "-PT10M"})
+	master.Children = append(master.Children, alarm)
+
+	moved := newTestEvent("weekly", "Moved sync", weeklyStart.AddDate(0, 0, 7).Add(time.Hour))
+	moved.Props.SetDateTime(ical.PropRecurrenceID, weeklyStart.AddDate(0, 0, 7))
+
+	cal := newCalendar(master)
+	cal.Children = append(cal.Children, moved)
+	if _, err := memory.Create("/personal/weekly.ics", cal); err != nil {
+		t.Fatal(err)
+	}
+
+	return memory
+}
+
+// applyTestMessage puts a message in the inbox and applies it
+func applyTestMessage(t *testing.T, memory *backendtest.Memory, method string, component *ical.Component) {
+
+	cal := newCalendar(component)
+	cal.Props.SetText(ical.PropMethod, method)
+	if _, err := memory.Create("/inbox/message.ics", cal); err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := findInboxMessage(fetchInboxMessages(), "message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applyInboxMessage(message); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// weeklyEvents returns the VEVENTs of the weekly event by RECURRENCE-ID, with "" for the event itself
+func weeklyEvents(t *testing.T, memory *backendtest.Memory) map[string]*ical.Component {
+
+	object, err := memory.Object("/personal/weekly.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := make(map[string]*ical.Component)
+	for _, child := range object.Data.Children {
+		if child.Name == ical.CompEvent {
+			events[model.PropValue(child, ical.PropRecurrenceID)] = child
+		}
+	}
+
+	return events
+}
+
+func TestApplyInboxRequestOccurrence(t *testing.T) {
+
+	memory := setupInbox(t)
+
+	// the organizer moves the third occurrence to another room
+	third := weeklyStart.AddDate(0, 0, 14)
+	request := newTestEvent("weekly", "Sync", third)
+	request.Props.SetDateTime(ical.PropRecurrenceID, third)
+	request.Props.SetText(ical.PropLocation, "Room B")
+	request.Props.Add(&ical.Prop{Name: ical.PropAttendee, Params: ical.Params{ical.ParamParticipationStatus: {"NEEDS-ACTION"}}, Value: testAddress})
+	applyTestMessage(t, memory, "REQUEST", request)
+
+	events := weeklyEvents(t, memory)
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want the event and 2 occurrences", len(events))
+	}
+	if master := events[""]; master == nil || master.Props.Get(ical.PropRecurrenceRule) == nil || len(master.Children) != 1 {
+		t.Fatal("the event, its recurrence or its alarm was lost")
+	}
+	if got := model.PropValue(events[weeklyStart.AddDate(0, 0, 7).Format("20060102T150405Z")], ical.PropSummary); got != "Moved sync" {
+		t.Errorf("got summary %q for the moved occurrence", got)
+	}
+
+	override := events[third.Format("20060102T150405Z")]
+	if override == nil {
+		t.Fatal("the occurrence wasn't added")
+	}
+	if got := model.PropValue(override, ical.PropLocation); got != "Room B" {
+		t.Errorf("got location %q", got)
+	}
+	if got := override.Props.Get(ical.PropAttendee).Params.Get(ical.ParamParticipationStatus); got != "ACCEPTED" {
+		t.Errorf("got participation status %q, want ACCEPTED", got)
+	}
+	if len(override.Children) != 1 || override.Children[0].Name != ical.CompAlarm {
+		t.Error("the occurrence doesn't have the alarm")
+	}
+}
+
+func TestApplyInboxRequestEvent(t *testing.T) {
+
+	memory := setupInbox(t)
+
+	request := newTestEvent("weekly", "Weekly sync", weeklyStart)
+	request.Props.Set(&ical.Prop{Name: ical.PropRecurrenceRule, Params: ical.Params{}, Value: "FREQ=WEEKLY"})
+	request.Props.Add(&ical.Prop{Name: ical.PropAttendee, Params: ical.Params{}, Value: testAddress})
+	applyTestMessage(t, memory, "REQUEST", request)
+
+	events := weeklyEvents(t, memory)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want the event and the moved occurrence", len(events))
+	}
+	if got := model.PropValue(events[""], ical.PropSummary); got != "Weekly sync" {
+		t.Errorf("got summary %q", got)
+	}
+	if got := events[""].Props.Get(ical.PropAttendee).Params.Get(ical.ParamParticipationStatus); got != "ACCEPTED" {
+		t.Errorf("got participation status %q, want ACCEPTED", got)
+	}
+}
+
+func TestApplyInboxRequestNew(t *testing.T) {
+
+	memory := setupInbox(t)
+	applyTestMessage(t, memory, "REQUEST", newTestEvent("new", "Kick-off", weeklyStart))
+
+	object, err := memory.Object("/personal/new.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object.Data.Props.Get(ical.PropMethod) != nil {
+		t.Error("the stored event has a method")
+	}
+}
+
+func TestApplyInboxCancel(t *testing.T) {
+
+	memory := setupInbox(t)
+
+	// the third occurrence isn't overridden, so it is excluded
+	third := weeklyStart.AddDate(0, 0, 14)
+	cancel := newTestEvent("weekly", "Sync", third)
+	cancel.Props.SetDateTime(ical.PropRecurrenceID, third)
+	applyTestMessage(t, memory, "CANCEL", cancel)
+
+	events := weeklyEvents(t, memory)
+	if got := model.PropValue(events[""], ical.PropExceptionDates); got != third.Format("20060102T150405Z") {
+		t.Errorf("got EXDATE %q", got)
+	}
+	if got := model.PropValue(events[""], ical.PropStatus); got != "" {
+		t.Errorf("got status %q for the event", got)
+	}
+
+	// an event that isn't in the calendars has nothing to cancel
+	if err := memory.Delete("/inbox/message.ics", ""); err != nil {
+		t.Fatal(err)
+	}
+	applyTestMessage(t, memory, "CANCEL", newTestEvent("unknown", "Other", weeklyStart))
+}
+
+func TestApplyInboxCounter(t *testing.T) {
+
+	memory := setupInbox(t)
+
+	counter := newTestEvent("weekly", "Sync", weeklyStart.Add(2*time.Hour))
+	applyTestMessage(t, memory, "COUNTER", counter)
+
+	master := weeklyEvents(t, memory)[""]
+	start, err := master.Props.DateTime(ical.PropDateTimeStart, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !start.Equal(weeklyStart.Add(2 * time.Hour)) {
+		t.Errorf("got start %v", start)
+	}
+	if got := model.PropValue(master, ical.PropSequence); got != "1" {
+		t.Errorf("got sequence %q, want 1", got)
+	}
+}
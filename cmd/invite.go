@@ -0,0 +1,178 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/config"
+	"tsundoku.dev/quickcal/imip"
+	"tsundoku.dev/quickcal/model"
+)
+
+// schedulingIdentity is how the user sends and answers invitations on a server
+type schedulingIdentity struct {
+	address   string   // the calendar address, without the mailto: scheme
+	addresses []string // all the calendar addresses of the user, to find the user among the attendees
+	name      string
+	email     bool // send iMIP emails instead of relying on server-side scheduling
+	sendmail  string
+}
+
+// serverIdentity returns the scheduling identity of the user on a server. Invitations are sent by email when
+// configured so, or when the server doesn't support RFC 6638 scheduling
+func serverIdentity(server *model.CalendarServer) (*schedulingIdentity, error) {
+
+	identity := &schedulingIdentity{}
+	if cfgServer := config.GetServerByName(&cfg, server.Name); cfgServer != nil {
+		identity.address = cfgServer.Email
+		identity.name = cfgServer.DisplayName
+		identity.email = cfgServer.Scheduling == "email"
+		identity.sendmail = cfgServer.Sendmail
+	}
+	if identity.email && identity.address != "" {
+		identity.addresses = []string{identity.address}
+		return identity, nil
+	}
+
+	scheduling, address, err := serverScheduling(server)
+	if err != nil {
+		if identity.address == "" {
+			return nil, err
+		}
+
+		identity.email = true
+		identity.addresses = []string{identity.address}
+		return identity, nil
+	}
+
+	identity.address = address
+	identity.addresses = append([]string{address}, scheduling.Addresses...)
+	if scheduling.OutboxURL == "" {
+		identity.email = true
+	}
+	if identity.address == "" {
+		return nil, fmt.Errorf("no email address found for server '%s', set it in the configuration file", server.Name)
+	}
+
+	return identity, nil
+}
+
+// calendarAddress returns the address as a property value, with the mailto: scheme
+func calendarAddress(address string) string {
+	return "mailto:" + model.Address(address)
+}
+
+// parseAttendee parses an attendee such as "bob@example.com;role=opt-participant;rsvp=false;cn=Bob".
+// The role defaults to REQ-PARTICIPANT, and RSVP to TRUE
+func parseAttendee(spec string) (ical.Prop, error) {
+
+	parts := strings.Split(spec, ";")
+	address := strings.TrimSpace(parts[0])
+	if !strings.Contains(address, "@") {
+		return ical.Prop{}, fmt.Errorf("invalid attendee address '%s'", address)
+	}
+
+	prop := ical.NewProp(ical.PropAttendee)
+	prop.Value = calendarAddress(address)
+	prop.Params.Set(ical.ParamRole, "REQ-PARTICIPANT")
+	prop.Params.Set(ical.ParamRSVP, "TRUE")
+	prop.Params.Set(ical.ParamParticipationStatus, model.PartStatNeedsAction)
+
+	for _, param := range parts[1:] {
+		key, value, found := strings.Cut(param, "=")
+		if !found {
+			return ical.Prop{}, fmt.Errorf("invalid attendee parameter '%s', use name=value", param)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "role":
+			role := strings.ToUpper(strings.TrimSpace(value))
+			switch role {
+			case "CHAIR", "REQ-PARTICIPANT", "OPT-PARTICIPANT", "NON-PARTICIPANT":
+				prop.Params.Set(ical.ParamRole, role)
+			default:
+				return ical.Prop{}, fmt.Errorf("invalid role '%s', use chair, req-participant, opt-participant or non-participant", value)
+			}
+		case "rsvp":
+			prop.Params.Set(ical.ParamRSVP, strings.ToUpper(strings.TrimSpace(value)))
+		case "cn":
+			prop.Params.Set(ical.ParamCommonName, strings.TrimSpace(value))
+		default:
+			return ical.Prop{}, fmt.Errorf("unknown attendee parameter '%s'", key)
+		}
+	}
+
+	return *prop, nil
+}
+
+// addAttendees writes the organizer and the attendees of an invitation. When invitations are sent by email, the
+// server is told not to deliver them with SCHEDULE-AGENT=CLIENT. It returns the addresses of the attendees
+func addAttendees(component *ical.Component, identity *schedulingIdentity, specs []string) ([]string, error) {
+
+	organizer := ical.NewProp(ical.PropOrganizer)
+	organizer.Value = calendarAddress(identity.address)
+	if identity.name != "" {
+		organizer.Params.Set(ical.ParamCommonName, identity.name)
+	}
+	component.Props.Set(organizer)
+
+	recipients := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		attendee, err := parseAttendee(spec)
+		if err != nil {
+			return nil, err
+		}
+		if identity.email {
+			attendee.Params.Set("SCHEDULE-AGENT", "CLIENT")
+		}
+
+		component.Props.Add(&attendee)
+		recipients = append(recipients, model.Address(attendee.Value))
+	}
+
+	component.Props.Set(&ical.Prop{Name: ical.PropSequence, Params: ical.Params{}, Value: "0"})
+
+	return recipients, nil
+}
+
+// sendITIP emails an iTIP message about a component, either through the configured sendmail command or by saving it
+// as a .eml file in emlDir
+func sendITIP(identity *schedulingIdentity, recipients []string, method string, subject string, text string, component *ical.Component, emlDir string) error {
+
+	msg, err := imip.Message(identity.address, recipients, subject, text, method, newCalendar(component))
+	if err != nil {
+		return err
+	}
+
+	if identity.sendmail != "" {
+		if err := imip.Send(identity.sendmail, msg); err != nil {
+			return fmt.Errorf("failed to send the email: %w", err)
+		}
+
+		fmt.Printf("Email sent to %s\n", strings.Join(recipients, ", "))
+		return nil
+	}
+
+	name := fmt.Sprintf("%s-%s", model.PropValue(component, ical.PropUID), strings.ToLower(method))
+	path, err := imip.WriteEML(emlDir, name, msg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Email saved to %s, send it to %s\n", path, strings.Join(recipients, ", "))
+	return nil
+}
@@ -0,0 +1,94 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	invitesCmdFlagFrom string
+	invitesCmdFlagTo   string
+)
+
+// invitesCmd represents the invites command
+var invitesCmd = &cobra.Command{
+	Use:   "invites",
+	Short: "Lists the invitations waiting for a reply",
+	Long: `
+Lists the events of the next 90 days where your participation status is NEEDS-ACTION, i.e. the invitations you haven't
+replied to yet. The flags "from" and "to" override the time range.
+
+Reply to them with "event respond [uid] accept|decline|tentative".
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		from := time.Now()
+		to := from.AddDate(0, 0, 90)
+
+		var err error
+		if invitesCmdFlagFrom != "" {
+			from, err = parseDateString(invitesCmdFlagFrom)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		if invitesCmdFlagTo != "" {
+			to, err = parseDateString(invitesCmdFlagTo)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+
+		addresses := userAddresses()
+		if len(addresses) == 0 {
+			log.Println("no email address found, set it in the configuration file")
+			return
+		}
+
+		found := false
+		for _, event := range fetchEvents(from, to, false) {
+			if event.Organizer == nil || model.SameAddress(event.Organizer.Address, addresses) {
+				continue
+			}
+
+			attendee := event.Attendee(addresses)
+			if attendee == nil || attendee.PartStat != model.PartStatNeedsAction {
+				continue
+			}
+
+			found = true
+			_, _ = event.Calendar.Color.Printf("%s\t%s\t%s\tfrom %s\t%s\n", event.Start.Format("Mon 02/01/2006 15:04"),
+				event.Calendar.Name, event.Summary, event.Organizer, event.UID)
+		}
+
+		if !found {
+			fmt.Println("No pending invitations")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(invitesCmd)
+
+	invitesCmd.Flags().StringVar(&invitesCmdFlagFrom, "from", "", "List invitations from this date. Defaults to the current date")
+	invitesCmd.Flags().StringVar(&invitesCmdFlagTo, "to", "", "List invitations to this date. Defaults to 90 days after the current date")
+}
@@ -0,0 +1,84 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+)
+
+// journalCmd represents the journal command
+var journalCmd = &cobra.Command{
+	Use:   "journal",
+	Short: "Journal-related actions",
+	Long:  ``,
+}
+
+func init() {
+	rootCmd.AddCommand(journalCmd)
+}
+
+// fetchJournals queries all the tracked calendars that support journals for the entries between from and to,
+// sorted by date. If from and to are zero, all the entries are returned
+func fetchJournals(from time.Time, to time.Time) []*model.Journal {
+
+	query := backend.Query{Component: ical.CompJournal, Start: from, End: to}
+
+	var allJournals []*model.Journal
+	for _, caldavServer := range caldavServers {
+
+		for i := range caldavServer.Calendars {
+			calendar := &caldavServer.Calendars[i]
+			if !calendar.Supports(ical.CompJournal) {
+				continue
+			}
+
+			calendarObjects, err := queryCalendarObjects(&caldavServer, calendar, query)
+			if err != nil {
+				fmt.Println(err)
+			}
+
+			for _, calendarObject := range calendarObjects {
+				journals, err := model.NewJournals(calendarObject, calendar)
+				if err != nil {
+					fmt.Println(err)
+				}
+
+				// local objects aren't filtered by a server
+				for _, journal := range journals {
+					if from.IsZero() && to.IsZero() || journal.Date != nil && !journal.Date.Before(from) && journal.Date.Before(to) {
+						allJournals = append(allJournals, journal)
+					}
+				}
+			}
+		}
+	}
+
+	// entries without a date go last
+	sort.SliceStable(allJournals, func(i, j int) bool {
+		if (allJournals[i].Date == nil) != (allJournals[j].Date == nil) {
+			return allJournals[i].Date != nil
+		}
+
+		return allJournals[i].Date != nil && allJournals[i].Date.Before(*allJournals[j].Date)
+	})
+
+	return allJournals
+}
@@ -28,20 +28,7 @@ var listCalendarsCmd = &cobra.Command{
 
 		for _, server := range caldavServers {
 
-			caldavClient := server.Client
-			principal, err := caldavClient.FindCurrentUserPrincipal()
-			if err != nil {
-				fmt.Printf("Error finding current user principal: %s\n", err)
-				return
-			}
-
-			homeset, err := caldavClient.FindCalendarHomeSet(principal)
-			if err != nil {
-				fmt.Printf("Error finding calendar home set: %s\n", err)
-				return
-			}
-
-			calendars, err := caldavClient.FindCalendars(homeset)
+			calendars, err := server.Backend.Calendars()
 			if err != nil {
 				fmt.Printf("Error finding calendars: %s\n", err)
 				return
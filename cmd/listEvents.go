@@ -16,6 +16,7 @@ package cmd
 import (
 	"fmt"
 	"log"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -23,13 +24,18 @@ import (
 	"github.com/emersion/go-ical"
 	"github.com/emersion/go-webdav/caldav"
 	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
 	"tsundoku.dev/quickcal/constants"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/export"
 	"tsundoku.dev/quickcal/model"
 )
 
 var (
 	fromDateStr string
 	toDateStr   string
+	listOutput  string
+	listFilter  eventFilter
 )
 
 // listCmd represents the list command
@@ -40,77 +46,163 @@ var eventsListCmd = &cobra.Command{
 Lists all the events, in the next 7 days, on the default calendar. A different calendar can be specified via flags.
 
 The flags "from" and "to"" can be used to override the search time range.
+
+The events can be filtered by status, category, location, attendee, minimum duration, and whether they last all day.
+"hide-declined" hides the invitations you declined.
+
+The events are read from a local cache, which only downloads the events that changed since the last listing.
+
+The flag "output" sets the output format: "text" (default), "org" for an org-mode agenda, or "markdown".
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 
-		var err error
-		from := time.Now()
-		if fromDateStr != "" {
-			from, err = parseDateString(fromDateStr)
-			if err != nil {
-				log.Println(err)
-				return
-			}
+		from, to, err := parseTimeRange(fromDateStr, toDateStr)
+		if err != nil {
+			log.Println(err)
+			return
 		}
 
-		to := from.Add(7 * 24 * time.Hour)
-		if toDateStr != "" {
-			to, err = parseDateString(toDateStr)
-			if err != nil {
-				log.Println(err)
-				return
+		if err := listFilter.validate(); err != nil {
+			log.Println(err)
+			return
+		}
+
+		allEvents := listFilter.apply(fetchFilteredEvents(from, to, true, listFilter.propFilters()))
+
+		switch listOutput {
+		case "org":
+			err = export.OrgAgenda(os.Stdout, allEvents)
+		case "markdown":
+			err = export.MarkdownAgenda(os.Stdout, allEvents)
+		case "text":
+			for _, zc := range allEvents {
+				_, _ = zc.Calendar.Color.Println(zc)
 			}
+		default:
+			err = fmt.Errorf("unknown output format '%s'", listOutput)
+		}
+		if err != nil {
+			log.Println(err)
+		}
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(eventsListCmd)
+
+	eventsListCmd.Flags().StringVar(&fromDateStr, "from", "", "List events from this date. Defaults to the current date")
+	eventsListCmd.Flags().StringVar(&toDateStr, "to", "", "List events to this date. Defaults to 7 days after the from date")
+	eventsListCmd.Flags().StringVarP(&listOutput, "output", "o", "text", "Output format: text, org or markdown")
+	eventsListCmd.Flags().StringVar(&listFilter.status, "status", "", "Only list events with this status: confirmed, tentative or cancelled")
+	eventsListCmd.Flags().StringVar(&listFilter.category, "category", "", "Only list events with this category")
+	eventsListCmd.Flags().StringVar(&listFilter.location, "location", "", "Only list events whose location contains this text")
+	eventsListCmd.Flags().StringVar(&listFilter.attendee, "attendee", "", "Only list events with an attendee whose address or name contains this text")
+	eventsListCmd.Flags().DurationVar(&listFilter.minDuration, "min-duration", 0, "Only list events lasting at least this duration, e.g. 30m")
+	eventsListCmd.Flags().BoolVar(&listFilter.allDay, "all-day", false, "Only list all-day events")
+	eventsListCmd.Flags().BoolVar(&listFilter.timed, "timed", false, "Only list events with a start time")
+	eventsListCmd.Flags().BoolVar(&listFilter.hideDeclined, "hide-declined", false, "Hide the events you declined")
+	eventsListCmd.MarkFlagsMutuallyExclusive("all-day", "timed")
+}
+
+// parseTimeRange parses the from and to flags. from defaults to the current time, and to defaults to 7 days after from
+func parseTimeRange(fromStr string, toStr string) (time.Time, time.Time, error) {
+
+	var err error
+	from := time.Now()
+	if fromStr != "" {
+		from, err = parseDateString(fromStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
 		}
+	}
 
-		query := caldav.CalendarQuery{
-			CompFilter: caldav.CompFilter{
-				Name: ical.CompCalendar,
-				Comps: []caldav.CompFilter{
-					{
-						Name:  ical.CompEvent,
-						Start: from,
-						End:   to,
-					},
-				},
-			},
+	to := from.Add(7 * 24 * time.Hour)
+	if toStr != "" {
+		to, err = parseDateString(toStr)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
 		}
+	}
+
+	return from, to, nil
+}
+
+// fetchEvents queries all the tracked calendars for events between from and to, sorted by start time.
+// If expand is false, recurrent events are returned once, with their recurrence rule
+func fetchEvents(from time.Time, to time.Time, expand bool) []*model.CalendarObject {
+	return fetchFilteredEvents(from, to, expand, nil)
+}
+
+// fetchFilteredEvents works like fetchEvents, but the servers only return the events matching all the prop filters.
+// Calendars whose server doesn't support the filters, or that are read from the cache, return all their events
+func fetchFilteredEvents(from time.Time, to time.Time, expand bool, filters []dav.PropFilter) []*model.CalendarObject {
 
-		var allEvents []*model.CalendarObject
-		for _, caldavServer := range caldavServers {
+	query := backend.Query{Component: ical.CompEvent, Start: from, End: to, Filters: filters}
 
-			for _, calendar := range caldavServer.Calendars {
+	var allEvents []*model.CalendarObject
+	for _, caldavServer := range caldavServers {
+		caldavServer := caldavServer
 
-				calendarObjects, err := caldavServer.Client.QueryCalendar(calendar.Path, &query)
+		for i := range caldavServer.Calendars {
+			calendar := &caldavServer.Calendars[i]
+
+			if len(filters) > 0 {
+				calendarObjects, err := queryCalendarObjects(&caldavServer, calendar, query)
 				if err != nil {
-					fmt.Println(err)
+					log.Printf("failed to query calendar '%s': %v", calendar.Name, err)
+					continue
 				}
 
-				for _, calendarObject := range calendarObjects {
-					zcs, err := model.NewCalendarObjects(calendarObject, from, to, &calendar)
-					if err != nil {
-						fmt.Println(err)
-					}
+				allEvents = append(allEvents, newEvents(calendarObjects, from, to, expand, calendar)...)
+				continue
+			}
 
-					allEvents = append(allEvents, zcs...)
+			calendarObjects, err := cachedCalendarObjects(&caldavServer, calendar)
+			if err != nil && offline {
+				log.Printf("failed to read the cache of calendar '%s': %v", calendar.Name, err)
+				continue
+			}
+			if err != nil {
+				log.Printf("failed to sync the cache of calendar '%s': %v", calendar.Name, err)
+
+				calendarObjects, err = caldavServer.Backend.Query(calendar.Path, query)
+				if err != nil {
+					log.Printf("failed to query calendar '%s': %v", calendar.Name, err)
+					continue
 				}
 			}
+
+			allEvents = append(allEvents, newEvents(calendarObjects, from, to, expand, calendar)...)
 		}
+	}
 
-		sort.Slice(allEvents, func(i, j int) bool {
-			return allEvents[i].Start.Before(*allEvents[j].Start)
-		})
+	sort.Slice(allEvents, func(i, j int) bool {
+		return allEvents[i].Start.Before(*allEvents[j].Start)
+	})
 
-		for _, zc := range allEvents {
-			_, _ = zc.Calendar.Color.Println(zc)
-		}
-	},
+	return allEvents
 }
 
-func init() {
-	eventCmd.AddCommand(eventsListCmd)
+// newEvents returns the events of the calendar objects between from and to, with the recurrent ones expanded or not
+func newEvents(calendarObjects []caldav.CalendarObject, from time.Time, to time.Time, expand bool, calendar *model.Calendar) []*model.CalendarObject {
 
-	eventsListCmd.Flags().StringVar(&fromDateStr, "from", "", "List events from this date. Defaults to the current date")
-	eventsListCmd.Flags().StringVar(&toDateStr, "to", "", "List events to this date. Defaults to 7 days after the from date")
+	events := make([]*model.CalendarObject, 0, len(calendarObjects))
+	for _, calendarObject := range calendarObjects {
+		var zcs []*model.CalendarObject
+		var err error
+		if expand {
+			zcs, err = model.NewCalendarObjects(calendarObject, from, to, calendar)
+		} else {
+			zcs, err = model.NewUnexpandedCalendarObjects(calendarObject, from, to, calendar)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+
+		events = append(events, zcs...)
+	}
+
+	return events
 }
 
 func parseDateString(dateStr string) (time.Time, error) {
@@ -128,3 +220,30 @@ func parseDateString(dateStr string) (time.Time, error) {
 
 	return inputTime, nil
 }
+
+// parseDateTimeString parses a date, dd/mm or dd/mm/yyyy, optionally followed by a time, hh:mm, in the given location.
+// It also reports whether the string only contains a date
+func parseDateTimeString(dateTimeStr string, location *time.Location) (time.Time, bool, error) {
+
+	parts := strings.Fields(dateTimeStr)
+	if len(parts) == 0 || len(parts) > 2 {
+		return time.Time{}, false, fmt.Errorf("invalid date '%s'", dateTimeStr)
+	}
+
+	date, err := parseDateString(parts[0])
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if len(parts) == 1 {
+		return date, true, nil
+	}
+
+	fullInputStr := fmt.Sprintf("%s %s", date.Format(constants.TimeLayoutInputDate), parts[1])
+	dateTime, err := time.ParseInLocation(constants.TimeLayoutInputDateTime, fullInputStr, location)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return dateTime, false, nil
+}
@@ -0,0 +1,105 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/dav"
+)
+
+func TestFetchFilteredEvents(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	weekly := newTestEvent("weekly", "Standup", day.AddDate(0, 0, -14))
+	weekly.Props.Set(&ical.Prop{Name: ical.PropRecurrenceRule, Params: ical.Params{}, Value: "FREQ=WEEKLY"})
+
+	for path, event := range map[string]*ical.Component{
+		"/personal/review.ics": newTestEvent("review", "Review", day.Add(2*time.Hour)),
+		"/personal/later.ics":  newTestEvent("later", "Later", day.AddDate(0, 1, 0)),
+		"/work/weekly.ics":     weekly,
+	} {
+		if _, err := memory.Create(path, newCalendar(event)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events := fetchFilteredEvents(day.Add(-time.Hour), day.AddDate(0, 0, 8), true, nil)
+	if got, want := summaries(events), []string{"Standup", "Review", "Standup"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+
+	unexpanded := fetchFilteredEvents(day.Add(-time.Hour), day.AddDate(0, 0, 8), false, nil)
+	if got, want := summaries(unexpanded), []string{"Standup", "Review"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got unexpanded events %v, want %v", got, want)
+	}
+}
+
+func TestFetchFilteredEventsUnreachable(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if _, err := memory.Create("/personal/review.ics", newCalendar(newTestEvent("review", "Review", day))); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first listing fills the cache, which is read while the server is unreachable
+	fetchFilteredEvents(day.Add(-time.Hour), day.Add(time.Hour), true, nil)
+	memory.Unreachable = true
+
+	events := fetchFilteredEvents(day.Add(-time.Hour), day.Add(time.Hour), true, nil)
+	if got, want := summaries(events), []string{"Review"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+	if _, ok := staleCalendars["Personal"]; !ok {
+		t.Fatal("the calendar wasn't reported as stale")
+	}
+}
+
+func TestFetchFilteredEventsQuery(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if _, err := memory.Create("/personal/review.ics", newCalendar(newTestEvent("review", "Review", day))); err != nil {
+		t.Fatal(err)
+	}
+	filters := []dav.PropFilter{{Name: ical.PropSummary, Text: "Review"}}
+
+	// the server is queried, and the cache is filled by an unfiltered listing
+	events := fetchFilteredEvents(day.Add(-time.Hour), day.Add(time.Hour), true, filters)
+	if got, want := summaries(events), []string{"Review"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+	if _, ok := staleCalendars["Personal"]; ok {
+		t.Fatal("the calendar was read from the cache")
+	}
+
+	fetchFilteredEvents(day.Add(-time.Hour), day.Add(time.Hour), true, nil)
+	memory.Unreachable = true
+
+	events = fetchFilteredEvents(day.Add(-time.Hour), day.Add(time.Hour), true, filters)
+	if got, want := summaries(events), []string{"Review"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got cached events %v, want %v", got, want)
+	}
+	if _, ok := staleCalendars["Personal"]; !ok {
+		t.Fatal("the calendar wasn't reported as stale")
+	}
+}
@@ -0,0 +1,80 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	journalListCmdFlagFrom   string
+	journalListCmdFlagTo     string
+	journalListCmdFlagSearch string
+)
+
+// journalListCmd represents the journal list command
+var journalListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the journal entries of the last 7 days",
+	Long: `
+Lists the journal entries of the tracked calendars, grouped by day. By default the entries of the last 7 days are listed,
+the flags "from" and "to" can be used to override the time range.
+
+The flag "search" only lists the entries whose summary or body contain the given text.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		from, to, err := parseTimeRange(journalListCmdFlagFrom, journalListCmdFlagTo)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		// the default range looks back, as journal entries are written after the fact
+		if journalListCmdFlagFrom == "" && journalListCmdFlagTo == "" {
+			from, to = from.AddDate(0, 0, -7), from
+		}
+
+		currentDay := ""
+		for _, journal := range fetchJournals(from, to) {
+			if journalListCmdFlagSearch != "" && !journal.Matches(journalListCmdFlagSearch) {
+				continue
+			}
+
+			day := "No date"
+			if journal.Date != nil {
+				day = journal.Date.Format("Monday, 2 January 2006")
+			}
+
+			if day != currentDay {
+				fmt.Printf("\n%s\n%s\n", day, strings.Repeat("-", len(day)))
+				currentDay = day
+			}
+
+			_, _ = journal.Calendar.Color.Println(journal)
+		}
+	},
+}
+
+func init() {
+	journalCmd.AddCommand(journalListCmd)
+
+	journalListCmd.Flags().StringVar(&journalListCmdFlagFrom, "from", "", "List entries from this date. Defaults to 7 days ago, or to today when \"to\" is given")
+	journalListCmd.Flags().StringVar(&journalListCmdFlagTo, "to", "", "List entries to this date. Defaults to today, or to 7 days after \"from\" when it is given")
+	journalListCmd.Flags().StringVarP(&journalListCmdFlagSearch, "search", "s", "", "Only list the entries that contain this text")
+}
@@ -0,0 +1,211 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+)
+
+var todoListCmdFlagAll bool
+
+// todoListCmd represents the todo list command
+var todoListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the open tasks",
+	Long: `
+Lists the open tasks of all the tracked calendars that support tasks, sorted by due date and priority.
+Sub-tasks are indented under their parent task.
+
+The flag "all" includes completed and cancelled tasks.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		allTodos := fetchTodoObjects()
+
+		shownTodos := make([]*todoObject, 0, len(allTodos))
+		shown := make(map[string]bool)
+		for _, todo := range allTodos {
+			if todoListCmdFlagAll || todo.todo.IsOpen() {
+				shownTodos = append(shownTodos, todo)
+				shown[todo.todo.UID] = true
+			}
+		}
+
+		// sub-tasks are shown under their parent, or as top-level tasks if the parent isn't shown
+		children := todoChildren(shownTodos)
+		printed := make(map[string]bool)
+
+		var printTree func(todo *todoObject, depth int)
+		printTree = func(todo *todoObject, depth int) {
+			if printed[todo.todo.UID] {
+				return
+			}
+			printed[todo.todo.UID] = true
+
+			_, _ = todo.todo.Calendar.Color.Println(strings.Repeat("    ", depth) + todo.todo.String())
+			for _, child := range children[todo.todo.UID] {
+				printTree(child, depth+1)
+			}
+		}
+
+		for _, todo := range shownTodos {
+			if !shown[todo.todo.Parent] {
+				printTree(todo, 0)
+			}
+		}
+
+		// tasks in a RELATED-TO cycle have no top-level ancestor
+		for _, todo := range shownTodos {
+			printTree(todo, 0)
+		}
+	},
+}
+
+func init() {
+	todoCmd.AddCommand(todoListCmd)
+
+	todoListCmd.Flags().BoolVarP(&todoListCmdFlagAll, "all", "a", false, "Include completed and cancelled tasks")
+}
+
+// todoObject is a task along with the calendar object that contains it, so it can be updated
+type todoObject struct {
+	todo      *model.Todo
+	server    *model.CalendarServer
+	object    caldav.CalendarObject
+	component *ical.Component
+}
+
+// fetchTodoObjects queries all the tracked calendars that support tasks, and returns the tasks sorted by due date and priority
+func fetchTodoObjects() []*todoObject {
+
+	var allTodos []*todoObject
+	for _, caldavServer := range caldavServers {
+		caldavServer := caldavServer
+
+		for i := range caldavServer.Calendars {
+			calendar := &caldavServer.Calendars[i]
+			if !calendar.Supports(ical.CompToDo) {
+				continue
+			}
+
+			calendarObjects, err := queryCalendarObjects(&caldavServer, calendar, backend.Query{Component: ical.CompToDo})
+			if err != nil {
+				fmt.Println(err)
+			}
+
+			for _, calendarObject := range calendarObjects {
+				for _, child := range calendarObject.Data.Children {
+					if child.Name != ical.CompToDo {
+						continue
+					}
+
+					todo, err := model.NewTodo(child, calendar)
+					if err != nil {
+						fmt.Println(err)
+						continue
+					}
+
+					allTodos = append(allTodos, &todoObject{
+						todo:      todo,
+						server:    &caldavServer,
+						object:    calendarObject,
+						component: child,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(allTodos, func(i, j int) bool {
+		return todoBefore(allTodos[i].todo, allTodos[j].todo)
+	})
+
+	return allTodos
+}
+
+// findTodo looks for the task with the given UID in all the tracked calendars that support tasks.
+// It also returns all the tasks, to navigate the task hierarchy
+func findTodo(uid string) (*todoObject, []*todoObject, error) {
+
+	allTodos := fetchTodoObjects()
+	for _, todo := range allTodos {
+		if todo.todo.UID == uid {
+			return todo, allTodos, nil
+		}
+	}
+
+	return nil, allTodos, fmt.Errorf("task '%s' not found", uid)
+}
+
+// todoChildren maps the UID of each task to its sub-tasks
+func todoChildren(todos []*todoObject) map[string][]*todoObject {
+
+	children := make(map[string][]*todoObject)
+	for _, todo := range todos {
+		if todo.todo.Parent != "" {
+			children[todo.todo.Parent] = append(children[todo.todo.Parent], todo)
+		}
+	}
+
+	return children
+}
+
+// todoDescendants returns all the sub-tasks of a task, recursively
+func todoDescendants(uid string, children map[string][]*todoObject) []*todoObject {
+
+	descendants := make([]*todoObject, 0)
+	visited := map[string]bool{uid: true}
+
+	pending := children[uid]
+	for len(pending) > 0 {
+		todo := pending[0]
+		pending = pending[1:]
+
+		// RELATED-TO can contain cycles when it's edited by other clients
+		if visited[todo.todo.UID] {
+			continue
+		}
+		visited[todo.todo.UID] = true
+
+		descendants = append(descendants, todo)
+		pending = append(pending, children[todo.todo.UID]...)
+	}
+
+	return descendants
+}
+
+// todoBefore sorts tasks by due date, then by priority. Tasks without a due date or a priority go last
+func todoBefore(a *model.Todo, b *model.Todo) bool {
+
+	if (a.Due == nil) != (b.Due == nil) {
+		return a.Due != nil
+	}
+	if a.Due != nil && !a.Due.Equal(*b.Due) {
+		return a.Due.Before(*b.Due)
+	}
+
+	if (a.Priority == 0) != (b.Priority == 0) {
+		return a.Priority != 0
+	}
+
+	return a.Priority < b.Priority
+}
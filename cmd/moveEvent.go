@@ -0,0 +1,173 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	moveEventCmdFlagTo    string
+	moveEventCmdFlagForce bool
+)
+
+// moveEventCmd represents the event move command
+var moveEventCmd = &cobra.Command{
+	Use:   "move [uid]",
+	Short: "Moves an event to another calendar",
+	Long: `
+Moves the event with the given UID to another calendar, by name or path, keeping all its properties and alarms.
+
+Within a server, the event is moved with a WebDAV MOVE. Between servers, it is downloaded, created in the target
+calendar and then deleted from the source one. Moves that conflict with busy events of the target calendar, or with any
+occurrence of a recurrent event during the next year, are refused unless "force" is used.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't move events in offline mode")
+			return
+		}
+
+		event, targetServer, target, err := prepareEventTransfer(args[0], moveEventCmdFlagTo, moveEventCmdFlagForce)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if event.server.Name == targetServer.Name && event.calendar.Path == target.Path {
+			log.Printf("the event is already in calendar '%s'", target.Name)
+			return
+		}
+
+		if err := checkWritable(event.calendar); err != nil {
+			log.Println(err)
+			return
+		}
+
+		destination := target.Path + path.Base(event.object.Path)
+
+		mover, ok := event.server.Backend.(backend.Mover)
+		if ok && event.server.Name == targetServer.Name {
+			err = mover.Move(event.object.Path, destination, event.object.ETag)
+		} else {
+			err = copyEventObject(targetServer, destination, event.object.Data)
+			if err == nil {
+				if err := event.server.Backend.Delete(event.object.Path, event.object.ETag); err != nil {
+					log.Printf("the event was copied to '%s', but it couldn't be removed from '%s': %v", target.Name, event.calendar.Name, err)
+					return
+				}
+			}
+		}
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Printf("Moved '%s' to calendar '%s'\n", event.event.Summary, target.Name)
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(moveEventCmd)
+
+	moveEventCmd.Flags().StringVar(&moveEventCmdFlagTo, "to", "", "The target calendar, by name or path")
+	moveEventCmd.Flags().BoolVarP(&moveEventCmdFlagForce, "force", "f", false, "Move the event even if it conflicts with other events")
+
+	_ = moveEventCmd.MarkFlagRequired("to")
+}
+
+// prepareEventTransfer finds an event and the calendar it is moved or copied to, which must accept it. The event is
+// downloaded again, so it is transferred as stored on the server
+func prepareEventTransfer(uid string, to string, force bool) (*eventObject, *model.CalendarServer, *model.Calendar, error) {
+
+	event, err := findEvent(uid)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	targetServer, target, err := findCalendar(to)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := checkWritable(target); err != nil {
+		return nil, nil, nil, err
+	}
+	if !target.Supports(ical.CompEvent) {
+		return nil, nil, nil, fmt.Errorf("calendar '%s' doesn't accept events", target.Name)
+	}
+
+	object, err := event.server.Backend.Object(event.object.Path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	event.object = *object
+
+	// only the target calendar is checked, and not when it is the source one, where the event would conflict with itself
+	sameCalendar := event.server.Name == targetServer.Name && event.calendar.Path == target.Path
+	if event.event.IsBusy() && !sameCalendar {
+		ranges, err := eventRanges(event.event)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		if err := checkConflicts(to, ranges, []string{target.Path}, force); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return event, targetServer, target, nil
+}
+
+// eventRanges returns the busy time of an event, or of its occurrences during the next year if it is recurrent
+func eventRanges(event *model.CalendarObject) ([]interval, error) {
+
+	tz := conflictTimezone()
+	if event.Recurrence == nil {
+		return []interval{eventInterval(event, tz)}, nil
+	}
+
+	now := time.Now()
+	occurrences, err := event.Occurrences(now, now.AddDate(1, 0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]interval, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		ranges = append(ranges, eventInterval(occurrence, tz))
+	}
+
+	return ranges, nil
+}
+
+// copyEventObject creates the calendar of an event at the given path of the target server, without overwriting
+func copyEventObject(targetServer *model.CalendarServer, destination string, cal *ical.Calendar) error {
+
+	_, err := targetServer.Backend.Create(destination, cal)
+	if backend.IsConflict(err) {
+		return fmt.Errorf("'%s' already exists in the target calendar", path.Base(destination))
+	}
+
+	return err
+}
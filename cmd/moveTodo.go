@@ -0,0 +1,126 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+)
+
+var (
+	todoMoveCmdFlagParent string
+	todoMoveCmdFlagRoot   bool
+)
+
+// todoMoveCmd represents the todo move command
+var todoMoveCmd = &cobra.Command{
+	Use:   "move [uid]",
+	Short: "Changes the parent of a task",
+	Long: `
+Moves the task with the given UID under the task given with the "parent" flag, or makes it a top-level task with the
+"root" flag. A task can't be moved under one of its own sub-tasks.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if (todoMoveCmdFlagParent == "") == !todoMoveCmdFlagRoot {
+			log.Println("either --parent or --root must be set")
+			return
+		}
+
+		todo, allTodos, err := findTodo(args[0])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if todoMoveCmdFlagRoot {
+			setTodoParent(todo.component, "")
+		} else {
+			if todoMoveCmdFlagParent == todo.todo.UID {
+				log.Println("a task can't be its own parent")
+				return
+			}
+
+			parentFound := false
+			for _, other := range allTodos {
+				if other.todo.UID == todoMoveCmdFlagParent {
+					parentFound = true
+					break
+				}
+			}
+			if !parentFound {
+				log.Printf("task '%s' not found", todoMoveCmdFlagParent)
+				return
+			}
+
+			for _, descendant := range todoDescendants(todo.todo.UID, todoChildren(allTodos)) {
+				if descendant.todo.UID == todoMoveCmdFlagParent {
+					log.Println("a task can't be moved under one of its sub-tasks")
+					return
+				}
+			}
+
+			setTodoParent(todo.component, todoMoveCmdFlagParent)
+		}
+
+		now := time.Now().UTC()
+		todo.component.Props.SetDateTime(ical.PropDateTimeStamp, now)
+		todo.component.Props.SetDateTime(ical.PropLastModified, now)
+
+		if err := updateTodo(todo); err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Println("Moved:", todo.todo.Summary)
+	},
+}
+
+func init() {
+	todoCmd.AddCommand(todoMoveCmd)
+
+	todoMoveCmd.Flags().StringVar(&todoMoveCmdFlagParent, "parent", "", "UID of the new parent task")
+	todoMoveCmd.Flags().BoolVar(&todoMoveCmdFlagRoot, "root", false, "Make the task a top-level task")
+}
+
+// setTodoParent replaces the parent relationship of a task. Other relationships, such as siblings, are kept
+func setTodoParent(component *ical.Component, parentUID string) {
+
+	relations := make([]ical.Prop, 0)
+	for _, relatedTo := range component.Props.Values(ical.PropRelatedTo) {
+		relType := relatedTo.Params.Get(ical.ParamRelationshipType)
+		if relType != "" && !strings.EqualFold(relType, "PARENT") {
+			relations = append(relations, relatedTo)
+		}
+	}
+
+	if parentUID != "" {
+		relations = append(relations, ical.Prop{
+			Name:   ical.PropRelatedTo,
+			Params: ical.Params{ical.ParamRelationshipType: []string{"PARENT"}},
+			Value:  parentUID,
+		})
+	}
+
+	component.Props[ical.PropRelatedTo] = relations
+	if len(relations) == 0 {
+		component.Props.Del(ical.PropRelatedTo)
+	}
+}
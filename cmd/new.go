@@ -20,30 +20,47 @@ import (
 	"time"
 
 	"github.com/emersion/go-ical"
-	"github.com/emersion/go-webdav/caldav"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"tsundoku.dev/quickcal/constants"
+	"tsundoku.dev/quickcal/imip"
 	"tsundoku.dev/quickcal/model"
 )
 
 var newCmdFlagAlarm []time.Duration
 var newCmdFlagCalendar string
+var newCmdFlagForce bool
+var newCmdFlagConflictCalendars []string
+var newCmdFlagAttendees []string
+var newCmdFlagEmlDir string
 
 // newCmd represents the new command
 var newCmd = &cobra.Command{
 	Use:   "new [description] [date] [time]",
 	Short: "Adds a new event to the default calendar",
 	Long: `
-Creates a new event in the default calendar. The accepted parameters have some restrictions:
+Creates a new event in the default calendar, or in the one given with the "calendar" flag. The accepted parameters have some restrictions:
 
 - description: only a single string is accepted, if there are spaces, surround the description in double quotes
 - date: the format is either dd/mm (the current year is assumed) or dd/mm/yyyy
 - time: the format is hh:mm
+
+Events overlapping with busy events of the tracked calendars are not created unless "force" is used. Calendars with
+"rejectConflicts: true" in the configuration file, such as room bookings, never accept conflicting events.
+
+With "attendee", the event is an invitation organized by the email of the server. The server delivers it when it supports
+scheduling, otherwise, or with "scheduling: email" in the configuration file, an invitation email is piped to the
+"sendmail" command of the server, or saved as a .eml file.
 `,
 	Args: cobra.MinimumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 
+		// feeds are read-only
+		if _, calendar, err := findCalendar(newCmdFlagCalendar); err == nil && calendar.ReadOnly {
+			log.Printf("calendar '%s' is a read-only feed, pick another one with --calendar", calendar.Name)
+			return
+		}
+
 		eventComponent := ical.NewComponent(ical.CompEvent)
 
 		// UID
@@ -162,48 +179,58 @@ Creates a new event in the default calendar. The accepted parameters have some r
 			}
 		}
 
-		newCalendar := ical.NewCalendar()
-		// TODO: change name
-		newCalendar.Props[ical.PropProductID] = []ical.Prop{
-			{
-				Name:  ical.PropProductID,
-				Value: "-//QuickCal//CalDAV Client//EN",
-			},
-		}
-		newCalendar.Props[ical.PropVersion] = []ical.Prop{
-			{
-				Name:  ical.PropVersion,
-				Value: "2.0",
-			},
-		}
-		newCalendar.Children = append(newCalendar.Children, eventComponent)
-
-		var defaultCalendarClient *caldav.Client
-		var defaultCalendar *model.Calendar
-		for _, server := range caldavServers {
-			for _, calendar := range server.Calendars {
-				if calendar.Default {
-					defaultCalendarClient = server.Client
-					defaultCalendar = &calendar
-					break
-				}
-			}
+		// conflicts, the input is a wall-clock time in the configured time zone
+		start := time.Date(inputTime.Year(), inputTime.Month(), inputTime.Day(), inputTime.Hour(), inputTime.Minute(), 0, 0, tz)
+		end := start.Add(1 * time.Hour)
+		if len(args) == 2 {
+			end = start.AddDate(0, 0, 1)
 		}
 
-		if defaultCalendarClient == nil || defaultCalendar == nil {
-			log.Println("no default calendar found")
+		if err := checkConflicts(newCmdFlagCalendar, []interval{{start: start, end: end}}, newCmdFlagConflictCalendars, newCmdFlagForce); err != nil {
+			log.Println(err)
 			return
 		}
 
-		path := fmt.Sprintf("%s%s.ics", defaultCalendar.Path, uid)
+		// ORGANIZER and ATTENDEE
+		var identity *schedulingIdentity
+		var recipients []string
+		if len(newCmdFlagAttendees) > 0 {
+			server, _, err := findCalendar(newCmdFlagCalendar)
+			if err != nil {
+				log.Println(err)
+				return
+			}
 
-		calObject, err := defaultCalendarClient.PutCalendarObject(path, newCalendar)
+			identity, err = serverIdentity(server)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+
+			recipients, err = addAttendees(eventComponent, identity, newCmdFlagAttendees)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+
+		etag, err := createEvent(newCmdFlagCalendar, eventComponent)
 		if err != nil {
 			log.Println(err)
 			return
 		}
 
-		fmt.Println(calObject.ETag)
+		fmt.Println(etag)
+
+		// without server-side scheduling, the invitations are sent by email
+		if identity != nil && identity.email {
+			subject := fmt.Sprintf("Invitation: %s", args[0])
+			text := fmt.Sprintf("You are invited to %s, on %s\n", args[0], start.Format("Mon 02/01/2006 15:04 MST"))
+
+			if err := sendITIP(identity, recipients, imip.MethodRequest, subject, text, eventComponent, newCmdFlagEmlDir); err != nil {
+				log.Println(err)
+			}
+		}
 	},
 }
 
@@ -212,6 +239,25 @@ func init() {
 
 	newCmd.Flags().DurationSliceVarP(&newCmdFlagAlarm, "alarm", "a", nil, "Add an alarm (it can be used many times). Use h or m (e.g. --alarm 15m --alarm 1h, will create two alarms, one for 15 minutes and one for 1 hour before the event). ")
 	newCmd.Flags().StringVarP(&newCmdFlagCalendar, "calendar", "c", "", "Set the calendar to write this event into. Overrides the selected default calendar")
+	newCmd.Flags().BoolVarP(&newCmdFlagForce, "force", "f", false, "Create the event even if it conflicts with other events")
+	newCmd.Flags().StringArrayVar(&newCmdFlagAttendees, "attendee", nil, "Invite an attendee (it can be used many times), e.g. bob@example.com or \"bob@example.com;role=opt-participant;rsvp=false;cn=Bob\"")
+	newCmd.Flags().StringVar(&newCmdFlagEmlDir, "eml-dir", ".", "Directory for the invitation emails, when they are not sent by the server or a sendmail command")
+	newCmd.Flags().StringSliceVar(&newCmdFlagConflictCalendars, "conflicts-in", nil, "Only look for conflicts in the calendars with this name or path (it can be used many times). Defaults to all the calendars")
+}
+
+// createEvent uploads a new event to the calendar with the given name or path, or to the default calendar.
+// It returns the ETag of the event, which is empty if the event was queued
+func createEvent(calendarName string, eventComponent *ical.Component) (string, error) {
+
+	server, calendar, err := findCalendar(calendarName)
+	if err != nil {
+		return "", err
+	}
+
+	uid := model.PropValue(eventComponent, ical.PropUID)
+	path := fmt.Sprintf("%s%s.ics", calendar.Path, uid)
+
+	return createObject(server, calendar, path, newCalendar(eventComponent))
 }
 
 func parseAlarm(alarmDuration time.Duration, alarmDescription string) (*ical.Component, error) {
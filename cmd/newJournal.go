@@ -0,0 +1,166 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	journalNewCmdFlagDate     string
+	journalNewCmdFlagSummary  string
+	journalNewCmdFlagCalendar string
+)
+
+// journalNewCmd represents the journal new command
+var journalNewCmd = &cobra.Command{
+	Use:   "new [body]",
+	Short: "Adds a new journal entry to the default calendar",
+	Long: `
+Creates a new journal entry in the default calendar, or in the one given with the "calendar" flag. The calendar must
+support journals.
+
+The body is read from the argument. If the argument is "-", or if it is missing and the input is not a terminal, the
+body is read from the standard input. Otherwise $EDITOR is opened to write it.
+
+- date: the format is dd/mm or dd/mm/yyyy. Defaults to today
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		date := time.Now()
+		if journalNewCmdFlagDate != "" {
+			var err error
+			date, err = parseDateString(journalNewCmdFlagDate)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+
+		server, calendar, err := findCalendar(journalNewCmdFlagCalendar)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if !calendar.Supports(ical.CompJournal) {
+			log.Printf("calendar '%s' doesn't support journals", calendar.Name)
+			return
+		}
+
+		body, err := readBody(args)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if strings.TrimSpace(body) == "" {
+			log.Println("empty journal entry, nothing to save")
+			return
+		}
+
+		journalComponent := ical.NewComponent(ical.CompJournal)
+
+		uid := uuid.NewString()
+		journalComponent.Props.SetText(ical.PropUID, uid)
+		journalComponent.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+		journalComponent.Props.SetDate(ical.PropDateTimeStart, date)
+		journalComponent.Props.SetText(ical.PropDescription, strings.TrimRight(body, "\n"))
+		if journalNewCmdFlagSummary != "" {
+			journalComponent.Props.SetText(ical.PropSummary, journalNewCmdFlagSummary)
+		}
+
+		path := fmt.Sprintf("%s%s.ics", calendar.Path, uid)
+
+		etag, err := createObject(server, calendar, path, newCalendar(journalComponent))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Println(uid, etag)
+	},
+}
+
+func init() {
+	journalCmd.AddCommand(journalNewCmd)
+
+	journalNewCmd.Flags().StringVarP(&journalNewCmdFlagDate, "date", "d", "", "Date of the entry, as dd/mm[/yyyy]. Defaults to today")
+	journalNewCmd.Flags().StringVarP(&journalNewCmdFlagSummary, "summary", "s", "", "Summary of the entry")
+	journalNewCmd.Flags().StringVarP(&journalNewCmdFlagCalendar, "calendar", "c", "", "Set the calendar to write this entry into. Overrides the selected default calendar")
+}
+
+// readBody returns the text given as argument, the standard input when the argument is "-" or missing and the input
+// is not a terminal, or the text written in $EDITOR
+func readBody(args []string) (string, error) {
+
+	if len(args) == 1 && args[0] != "-" {
+		return args[0], nil
+	}
+
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return "", err
+	}
+
+	if len(args) == 1 || stat.Mode()&os.ModeCharDevice == 0 {
+		body, err := io.ReadAll(os.Stdin)
+		return string(body), err
+	}
+
+	return editText("")
+}
+
+// editText opens $EDITOR (vi by default) with the given text, and returns the edited text
+func editText(text string) (string, error) {
+
+	file, err := os.CreateTemp("", "quickcal-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+
+	editorCmd := exec.Command(editor[0], append(editor[1:], file.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("editor failed: %w", err)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	return string(edited), err
+}
@@ -0,0 +1,141 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+var (
+	todoNewCmdFlagDue         string
+	todoNewCmdFlagPriority    int
+	todoNewCmdFlagDescription string
+	todoNewCmdFlagCalendar    string
+	todoNewCmdFlagParent      string
+)
+
+// todoNewCmd represents the todo new command
+var todoNewCmd = &cobra.Command{
+	Use:   "new [summary]",
+	Short: "Adds a new task to the default calendar",
+	Long: `
+Creates a new task in the default calendar, or in the one given with the "calendar" flag. The calendar must support tasks.
+
+- due: the format is dd/mm or dd/mm/yyyy, optionally followed by the time as hh:mm (e.g. --due "20/10 18:00")
+- priority: from 1 (highest) to 9 (lowest)
+- parent: the UID of the parent task, for sub-tasks. The sub-task is created in the parent's calendar, unless "calendar" is set
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if todoNewCmdFlagPriority < 0 || todoNewCmdFlagPriority > 9 {
+			log.Println("the priority must be between 1 and 9")
+			return
+		}
+
+		tz, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		calendarName := todoNewCmdFlagCalendar
+		if todoNewCmdFlagParent != "" {
+			parent, _, err := findTodo(todoNewCmdFlagParent)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+
+			if calendarName == "" {
+				calendarName = parent.todo.Calendar.Path
+			}
+		}
+
+		server, calendar, err := findCalendar(calendarName)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+		if !calendar.Supports(ical.CompToDo) {
+			log.Printf("calendar '%s' doesn't support tasks", calendar.Name)
+			return
+		}
+
+		todoComponent := ical.NewComponent(ical.CompToDo)
+
+		uid := uuid.NewString()
+		todoComponent.Props.SetText(ical.PropUID, uid)
+		todoComponent.Props.SetDateTime(ical.PropDateTimeStamp, time.Now().UTC())
+		todoComponent.Props.SetDateTime(ical.PropCreated, time.Now().UTC())
+		todoComponent.Props.SetText(ical.PropSummary, args[0])
+		todoComponent.Props.SetText(ical.PropStatus, "NEEDS-ACTION")
+
+		if todoNewCmdFlagDescription != "" {
+			todoComponent.Props.SetText(ical.PropDescription, todoNewCmdFlagDescription)
+		}
+
+		if todoNewCmdFlagParent != "" {
+			setTodoParent(todoComponent, todoNewCmdFlagParent)
+		}
+
+		if todoNewCmdFlagPriority != 0 {
+			todoComponent.Props.Set(&ical.Prop{
+				Name:   ical.PropPriority,
+				Params: ical.Params{},
+				Value:  fmt.Sprintf("%d", todoNewCmdFlagPriority),
+			})
+		}
+
+		if todoNewCmdFlagDue != "" {
+			due, allDay, err := parseDateTimeString(todoNewCmdFlagDue, tz)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+
+			if allDay {
+				todoComponent.Props.SetDate(ical.PropDue, due)
+			} else {
+				todoComponent.Props.SetDateTime(ical.PropDue, due)
+			}
+		}
+
+		path := fmt.Sprintf("%s%s.ics", calendar.Path, uid)
+
+		etag, err := createObject(server, calendar, path, newCalendar(todoComponent))
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Println(uid, etag)
+	},
+}
+
+func init() {
+	todoCmd.AddCommand(todoNewCmd)
+
+	todoNewCmd.Flags().StringVarP(&todoNewCmdFlagDue, "due", "d", "", "Due date, as dd/mm[/yyyy] [hh:mm]")
+	todoNewCmd.Flags().IntVarP(&todoNewCmdFlagPriority, "priority", "p", 0, "Priority, from 1 (highest) to 9 (lowest)")
+	todoNewCmd.Flags().StringVar(&todoNewCmdFlagDescription, "description", "", "Description of the task")
+	todoNewCmd.Flags().StringVar(&todoNewCmdFlagParent, "parent", "", "UID of the parent task")
+	todoNewCmd.Flags().StringVarP(&todoNewCmdFlagCalendar, "calendar", "c", "", "Set the calendar to write this task into. Overrides the selected default calendar")
+}
@@ -0,0 +1,99 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/queue"
+)
+
+func TestCreateEvent(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	etag, err := createEvent("", newTestEvent("planning", "Planning", start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag == "" {
+		t.Fatal("the event was queued instead of created")
+	}
+
+	object, err := memory.Object("/personal/planning.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if object.ETag != etag {
+		t.Fatalf("got ETag %q, want %q", etag, object.ETag)
+	}
+
+	if _, err := createEvent("Work", newTestEvent("review", "Review", start)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := memory.Object("/work/review.ics"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same UID can't be created twice
+	if _, err := createEvent("", newTestEvent("planning", "Planning", start)); !backend.IsConflict(err) {
+		t.Fatalf("got error %v, want a conflict", err)
+	}
+}
+
+func TestCreateEventQueued(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	memory.Unreachable = true
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	etag, err := createEvent("", newTestEvent("planning", "Planning", start))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if etag != "" {
+		t.Fatalf("got ETag %q for a queued event", etag)
+	}
+
+	ops, err := queue.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 1 || ops[0].Action != queue.ActionCreate || ops[0].Path != "/personal/planning.ics" {
+		t.Fatalf("unexpected queue %v", ops)
+	}
+
+	// the queued event is listed while the server is unreachable
+	events := fetchFilteredEvents(start.Add(-time.Hour), start.Add(time.Hour), true, nil)
+	if got, want := summaries(events), []string{"Planning"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got events %v, want %v", got, want)
+	}
+}
+
+func TestCreateEventReadOnly(t *testing.T) {
+
+	setupMemoryServer(t)
+	server := caldavServers["memory"]
+	server.Calendars[1].ReadOnly = true
+
+	_, err := createEvent("Work", newTestEvent("review", "Review", time.Now()))
+	if !errors.Is(err, backend.ErrReadOnly) {
+		t.Fatalf("got error %v, want ErrReadOnly", err)
+	}
+}
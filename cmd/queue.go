@@ -0,0 +1,62 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/queue"
+)
+
+// queueCmd represents the queue command
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "changes waiting to be sent to the servers",
+	Long:  ``,
+}
+
+// queueListCmd represents the queue list command
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the changes waiting to be sent",
+	Long: `
+Lists the creations, updates and deletions that were made while offline, or while the server was unreachable.
+They are sent by "sync", or automatically by the next command run online.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		ops, err := queue.Load()
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if len(ops) == 0 {
+			fmt.Println("No queued changes")
+			return
+		}
+
+		for _, op := range ops {
+			fmt.Printf("%s\t%s\t%s\t%s/%s\t%s\n", op.ShortID(), op.Time.Format("Mon 02/01/2006 15:04"), op.Action,
+				op.Server, op.Calendar, op.Summary())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueListCmd)
+}
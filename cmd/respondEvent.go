@@ -0,0 +1,154 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/imip"
+	"tsundoku.dev/quickcal/model"
+)
+
+var respondCmdFlagComment string
+var respondCmdFlagEmlDir string
+
+// responses maps the answers of the respond command to participation statuses
+var responses = map[string]string{
+	"accept":    model.PartStatAccepted,
+	"decline":   model.PartStatDeclined,
+	"tentative": model.PartStatTentative,
+}
+
+// respondCmd represents the event respond command
+var respondCmd = &cobra.Command{
+	Use:   "respond [uid] accept|decline|tentative",
+	Short: "Replies to an invitation",
+	Long: `
+Replies to the invitation with the given UID, shown by the "invites" command, by setting your participation status.
+
+The server delivers the reply to the organizer when it supports scheduling. Otherwise, or with "scheduling: email" in
+the configuration file, a reply email is piped to the "sendmail" command of the server, or saved as a .eml file.
+`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		partStat, ok := responses[strings.ToLower(args[1])]
+		if !ok {
+			log.Printf("unknown response '%s', use accept, decline or tentative", args[1])
+			return
+		}
+
+		event, err := findEvent(args[0])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		if event.event.Organizer == nil {
+			log.Println("the event is not an invitation")
+			return
+		}
+
+		identity, err := serverIdentity(event.server)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		// every occurrence of a recurrent event gets the same answer
+		now := time.Now().UTC()
+		var reply *ical.Component
+		for _, component := range event.events() {
+			attendee := findAttendeeProp(component, identity.addresses)
+			if attendee == nil {
+				continue
+			}
+
+			attendee.Params.Set(ical.ParamParticipationStatus, partStat)
+			attendee.Params.Del(ical.ParamRSVP)
+
+			component.Props.SetDateTime(ical.PropDateTimeStamp, now)
+			component.Props.SetDateTime(ical.PropLastModified, now)
+			if respondCmdFlagComment != "" {
+				component.Props.SetText(ical.PropComment, respondCmdFlagComment)
+			}
+
+			if reply == nil {
+				reply = replyComponent(component, *attendee)
+			}
+		}
+
+		if reply == nil {
+			log.Println("you are not an attendee of this event")
+			return
+		}
+
+		if err := event.update(); err != nil {
+			log.Println(err)
+			return
+		}
+
+		fmt.Printf("Replied %s to: %s\n", strings.ToLower(partStat), event.event.Summary)
+
+		if identity.email {
+			subject := fmt.Sprintf("%s: %s", strings.ToUpper(partStat[:1])+strings.ToLower(partStat[1:]), event.event.Summary)
+			text := fmt.Sprintf("%s has replied %s to %s\n", identity.address, strings.ToLower(partStat), event.event.Summary)
+			if respondCmdFlagComment != "" {
+				text += "\n" + respondCmdFlagComment + "\n"
+			}
+
+			err := sendITIP(identity, []string{event.event.Organizer.Address}, imip.MethodReply, subject, text, reply, respondCmdFlagEmlDir)
+			if err != nil {
+				log.Println(err)
+			}
+		}
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(respondCmd)
+
+	respondCmd.Flags().StringVar(&respondCmdFlagComment, "comment", "", "Add a comment to the reply")
+	respondCmd.Flags().StringVar(&respondCmdFlagEmlDir, "eml-dir", ".", "Directory for the reply email, when it is not sent by the server or a sendmail command")
+}
+
+// findAttendeeProp returns the ATTENDEE property of the component with one of the given addresses, or nil
+func findAttendeeProp(component *ical.Component, addresses []string) *ical.Prop {
+
+	attendees := component.Props[ical.PropAttendee]
+	for i := range attendees {
+		if model.SameAddress(attendees[i].Value, addresses) {
+			return &attendees[i]
+		}
+	}
+
+	return nil
+}
+
+// replyComponent returns a copy of the event for an iTIP REPLY, which only contains the replying attendee
+func replyComponent(component *ical.Component, attendee ical.Prop) *ical.Component {
+
+	reply := ical.NewComponent(ical.CompEvent)
+	for name, props := range component.Props {
+		reply.Props[name] = props
+	}
+	reply.Props[ical.PropAttendee] = []ical.Prop{attendee}
+
+	return reply
+}
@@ -0,0 +1,151 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"path"
+
+	"github.com/emersion/go-ical"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/backup"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	restoreCmdFlagCalendars []string
+	restoreCmdFlagTo        string
+	restoreCmdFlagOverwrite bool
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [backup]",
+	Short: "Restores calendars from a backup",
+	Long: `
+Recreates the objects of a backup made with "backup". Each calendar is restored into the tracked calendar with the same
+server and path, or else with the same name, so a backup can be restored on another server after adding its calendars
+with "calendar config". Use "to" to restore everything into a single calendar, and "calendar" to only restore some of
+the calendars of the backup.
+
+Objects that already exist are reported as conflicts and left untouched, unless "overwrite" is used.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't restore in offline mode")
+			return
+		}
+
+		objects, err := backup.Read(args[0])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		// the objects are grouped by calendar, in the order of the backup
+		calendars := make([]string, 0)
+		byCalendar := make(map[string][]backup.Object)
+		for _, object := range objects {
+			if !calendarSelected(&model.Calendar{Name: object.Calendar, Path: object.CalendarPath}, restoreCmdFlagCalendars) {
+				continue
+			}
+
+			key := object.Server + "\x00" + object.CalendarPath
+			if _, ok := byCalendar[key]; !ok {
+				calendars = append(calendars, key)
+			}
+			byCalendar[key] = append(byCalendar[key], object)
+		}
+
+		for _, key := range calendars {
+			calendarObjects := byCalendar[key]
+
+			server, calendar, err := restoreTarget(calendarObjects[0].Entry)
+			if err == nil {
+				err = checkWritable(calendar)
+			}
+			if err != nil {
+				log.Printf("can't restore calendar '%s': %v", calendarObjects[0].Calendar, err)
+				continue
+			}
+
+			restored, conflicts, failed := 0, 0, 0
+			for _, object := range calendarObjects {
+				err := restoreObject(server, calendar, object)
+				switch {
+				case err == nil:
+					restored++
+				case backend.IsConflict(err):
+					conflicts++
+					fmt.Printf("Conflict: '%s' already exists in calendar '%s'\n", path.Base(object.Href), calendar.Name)
+				default:
+					failed++
+					log.Printf("failed to restore '%s': %v", object.Href, err)
+				}
+			}
+
+			fmt.Printf("Restored %d objects of '%s' into calendar '%s': %d conflicts, %d failed\n",
+				restored, calendarObjects[0].Calendar, calendar.Name, conflicts, failed)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().StringSliceVarP(&restoreCmdFlagCalendars, "calendar", "c", nil, "Only restore the backed up calendars with this name or path (it can be used many times)")
+	restoreCmd.Flags().StringVar(&restoreCmdFlagTo, "to", "", "Restore into this calendar, by name or path")
+	restoreCmd.Flags().BoolVar(&restoreCmdFlagOverwrite, "overwrite", false, "Replace the objects that already exist")
+}
+
+// restoreTarget returns the tracked calendar a backed up calendar is restored into
+func restoreTarget(entry backup.Entry) (*model.CalendarServer, *model.Calendar, error) {
+
+	if restoreCmdFlagTo != "" {
+		return findCalendar(restoreCmdFlagTo)
+	}
+
+	if server, ok := caldavServers[entry.Server]; ok {
+		for i := range server.Calendars {
+			if server.Calendars[i].Path == entry.CalendarPath {
+				return &server, &server.Calendars[i], nil
+			}
+		}
+	}
+
+	return findCalendar(entry.Calendar)
+}
+
+// restoreObject creates a backed up object in a calendar, keeping its file name
+func restoreObject(server *model.CalendarServer, calendar *model.Calendar, object backup.Object) error {
+
+	cal, err := ical.NewDecoder(bytes.NewReader(object.Data)).Decode()
+	if err != nil {
+		return err
+	}
+
+	objectPath := calendar.Path + path.Base(object.Href)
+
+	_, err = server.Backend.Create(objectPath, cal)
+	if backend.IsConflict(err) && restoreCmdFlagOverwrite {
+		_, err = server.Backend.Update(objectPath, cal, "")
+	}
+
+	return err
+}
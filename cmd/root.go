@@ -17,11 +17,9 @@ import (
 	"log"
 	"os"
 
-	"github.com/emersion/go-webdav"
-	"github.com/emersion/go-webdav/caldav"
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"tsundoku.dev/quickcal/backend"
 	"tsundoku.dev/quickcal/config"
 	"tsundoku.dev/quickcal/model"
 )
@@ -35,6 +33,15 @@ var rootCmd = &cobra.Command{
 	Use:   "calendar",
 	Short: "A cli caldav client.",
 	Long:  ``,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// changes queued while offline are sent by the next command run online
+		if !offline && cmd != syncCmd && cmd.Parent() != queueCmd {
+			replayQueue()
+		}
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		warnStale()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -50,6 +57,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.calendar.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "read events from the local cache, without connecting to the servers")
 
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 
@@ -61,61 +69,127 @@ func init() {
 
 		caldavServers = make(map[string]model.CalendarServer)
 
-		// Create a client for each server
+		// Create a backend for each server
 		for _, server := range cfg.Servers {
-			if server.URL == "" || server.User == "" || server.Password == "" {
-				log.Printf("Skipping server '%s' due to missing configuration", server.Name)
+			if server.Type == "vdir" {
+				vdirBackend, err := backend.NewVdir(server.Path)
+				if err != nil {
+					log.Printf("Failed to open vdir '%s': %v", server.Name, err)
+					continue
+				}
+
+				caldavServers[server.Name] = model.CalendarServer{
+					Name:      server.Name,
+					Backend:   vdirBackend,
+					Calendars: vdirCalendars(server, vdirBackend),
+				}
 				continue
 			}
 
-			httpClient := webdav.HTTPClientWithBasicAuth(nil, server.User, server.Password)
+			if server.Type == "ics" {
+				icsBackend, err := backend.NewICS(server.URL)
+				if err != nil {
+					log.Printf("Failed to open feed '%s': %v", server.Name, err)
+					continue
+				}
 
-			client, err := caldav.NewClient(httpClient, server.URL)
-			if err != nil {
-				log.Printf("Failed to create client for server '%s': %v", server.Name, err)
+				caldavServers[server.Name] = model.CalendarServer{
+					Name:      server.Name,
+					Backend:   icsBackend,
+					Calendars: feedCalendars(server),
+				}
 				continue
 			}
 
-			calendars := make([]model.Calendar, 0, len(server.Calendars))
-			for _, calendar := range server.Calendars {
-
-				var calendarColor *color.Color
-				switch calendar.Color {
-				case "black":
-					calendarColor = color.New(color.FgBlack)
-				case "red":
-					calendarColor = color.New(color.FgRed)
-				case "green":
-					calendarColor = color.New(color.FgGreen)
-				case "yellow":
-					calendarColor = color.New(color.FgYellow)
-				case "blue":
-					calendarColor = color.New(color.FgBlue)
-				case "magenta":
-					calendarColor = color.New(color.FgMagenta)
-				case "cyan":
-					calendarColor = color.New(color.FgCyan)
-				case "white":
-					calendarColor = color.New(color.FgWhite)
-				}
+			if server.URL == "" || server.User == "" || server.Password == "" {
+				log.Printf("Skipping server '%s' due to missing configuration", server.Name)
+				continue
+			}
 
-				calendars = append(calendars, model.Calendar{
-					Name:    calendar.Name,
-					Path:    calendar.Path,
-					Color:   calendarColor,
-					Default: calendar.Default,
-				})
+			caldavBackend, err := backend.NewCalDAV(server.URL, server.User, server.Password)
+			if err != nil {
+				log.Printf("Failed to create client for server '%s': %v", server.Name, err)
+				continue
 			}
 
 			caldavServers[server.Name] = model.CalendarServer{
 				Name:      server.Name,
-				Client:    client,
-				Calendars: calendars,
+				Backend:   caldavBackend,
+				Calendars: newCalendars(server.Calendars),
 			}
 		}
 	})
 }
 
+// newCalendars creates the tracked calendars of a server from the configuration file
+func newCalendars(cfgCalendars []*config.Calendar) []model.Calendar {
+
+	calendars := make([]model.Calendar, 0, len(cfgCalendars))
+	for _, calendar := range cfgCalendars {
+
+		calendarColor, err := model.NewColor(calendar.Color)
+		if err != nil {
+			log.Printf("Invalid color for calendar '%s': %v", calendar.Name, err)
+		}
+
+		calendars = append(calendars, model.Calendar{
+			Name:            calendar.Name,
+			Path:            calendar.Path,
+			Color:           calendarColor,
+			ColorSpec:       calendar.Color,
+			Default:         calendar.Default,
+			Components:      calendar.Components,
+			RejectConflicts: calendar.RejectConflicts,
+		})
+	}
+
+	return calendars
+}
+
+// vdirCalendars returns the tracked calendars of a vdir. Without calendars in the configuration file, all the
+// calendar directories are tracked, with the color written by vdirsyncer
+func vdirCalendars(server *config.Server, vdirBackend backend.Backend) []model.Calendar {
+
+	if len(server.Calendars) > 0 {
+		return newCalendars(server.Calendars)
+	}
+
+	dirs, err := vdirBackend.Calendars()
+	if err != nil {
+		log.Printf("Failed to read vdir '%s': %v", server.Name, err)
+		return nil
+	}
+
+	cfgCalendars := make([]*config.Calendar, 0, len(dirs))
+	for _, dir := range dirs {
+		calendarColor, err := model.NormalizeColor(dir.Color)
+		if err != nil {
+			calendarColor = ""
+		}
+
+		cfgCalendars = append(cfgCalendars, &config.Calendar{Name: dir.Name, Path: dir.Path, Color: calendarColor})
+	}
+
+	return newCalendars(cfgCalendars)
+}
+
+// feedCalendars returns the tracked calendar of an ICS feed, which is named after the server unless it is configured.
+// Feeds are read-only
+func feedCalendars(server *config.Server) []model.Calendar {
+
+	cfgCalendars := server.Calendars
+	if len(cfgCalendars) == 0 {
+		cfgCalendars = []*config.Calendar{{Name: server.Name, Path: "/"}}
+	}
+
+	calendars := newCalendars(cfgCalendars)
+	for i := range calendars {
+		calendars[i].ReadOnly = true
+	}
+
+	return calendars
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
@@ -0,0 +1,255 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/dav"
+	"tsundoku.dev/quickcal/model"
+)
+
+var (
+	searchCmdFlagFrom  string
+	searchCmdFlagTo    string
+	searchCmdFlagRegex bool
+)
+
+// searchFields are the properties searched when the query doesn't specify one
+var searchFields = []string{ical.PropSummary, ical.PropDescription, ical.PropLocation}
+
+// eventsSearchCmd represents the event search command
+var eventsSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Searches the events by summary, description or location",
+	Long: `
+Searches the events of all the tracked calendars, from a year ago to a year from now, whose summary, description or
+location contain the query, case-insensitively. The flags "from" and "to" override the time range.
+
+A single field can be searched by prefixing the query with its name, e.g. "summary:vendor call", "description:..." or
+"location:...". With "regex", the query is a regular expression.
+
+The search runs on the server when it supports text matching, and falls back to matching the events locally otherwise.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		now := time.Now()
+		from, to := now.AddDate(-1, 0, 0), now.AddDate(1, 0, 0)
+
+		var err error
+		if searchCmdFlagFrom != "" {
+			from, err = parseDateString(searchCmdFlagFrom)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+		if searchCmdFlagTo != "" {
+			to, err = parseDateString(searchCmdFlagTo)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+		}
+
+		fields, text := parseSearchQuery(args[0])
+		matches, err := newTextMatcher(text, searchCmdFlagRegex)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		var found []*model.CalendarObject
+		for _, server := range caldavServers {
+			server := server
+			for i := range server.Calendars {
+				calendar := &server.Calendars[i]
+				if !calendar.Supports(ical.CompEvent) {
+					continue
+				}
+
+				// regular expressions can't be sent to the server
+				queryFields := fields
+				if searchCmdFlagRegex {
+					queryFields = nil
+				}
+
+				objects, err := searchCalendar(&server, calendar, queryFields, text, from, to)
+				if err != nil {
+					log.Printf("failed to search calendar '%s': %v", calendar.Name, err)
+					continue
+				}
+
+				for _, object := range objects {
+					events, err := model.NewUnexpandedCalendarObjects(object, from, to, calendar)
+					if err != nil {
+						log.Println(err)
+						continue
+					}
+
+					for _, event := range events {
+						if eventMatches(event, fields, matches) {
+							found = append(found, firstInRange(event, from, to))
+						}
+					}
+				}
+			}
+		}
+
+		if len(found) == 0 {
+			fmt.Println("No events found")
+			return
+		}
+
+		sort.Slice(found, func(i, j int) bool {
+			return found[i].Start.Before(*found[j].Start)
+		})
+
+		for _, event := range found {
+			line := fmt.Sprintf("%s\t%s\t%s", event.Start.Format("Mon 02/01/2006 15:04"), event.Calendar.Name, event.Summary)
+			if event.AllDay {
+				line = fmt.Sprintf("%s\t%s\t%s", event.Start.Format("Mon 02/01/2006"), event.Calendar.Name, event.Summary)
+			}
+			if event.Recurrence != nil {
+				line += " (recurrent)"
+			}
+			line += "\t" + event.UID
+
+			_, _ = event.Calendar.Color.Println(line)
+		}
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(eventsSearchCmd)
+
+	eventsSearchCmd.Flags().StringVar(&searchCmdFlagFrom, "from", "", "Search events from this date. Defaults to a year ago")
+	eventsSearchCmd.Flags().StringVar(&searchCmdFlagTo, "to", "", "Search events to this date. Defaults to a year from now")
+	eventsSearchCmd.Flags().BoolVarP(&searchCmdFlagRegex, "regex", "r", false, "The query is a regular expression")
+}
+
+// firstInRange returns a recurrent event as its first occurrence between from and to, so it is listed at a date within
+// the search range. The recurrence rule is kept, so it is still shown as recurrent
+func firstInRange(event *model.CalendarObject, from time.Time, to time.Time) *model.CalendarObject {
+
+	if event.Recurrence == nil {
+		return event
+	}
+
+	occurrences, err := event.Occurrences(from, to)
+	if err != nil || len(occurrences) == 0 {
+		return event
+	}
+
+	first := *occurrences[0]
+	first.Recurrence = event.Recurrence
+
+	return &first
+}
+
+// parseSearchQuery splits a query such as "location:office" into the searched properties and the text
+func parseSearchQuery(query string) ([]string, string) {
+	for _, field := range searchFields {
+		prefix := strings.ToLower(field) + ":"
+		if strings.HasPrefix(strings.ToLower(query), prefix) {
+			return []string{field}, query[len(prefix):]
+		}
+	}
+
+	return searchFields, query
+}
+
+// newTextMatcher returns a case-insensitive matcher for a text or a regular expression
+func newTextMatcher(text string, regex bool) (func(string) bool, error) {
+	if !regex {
+		lowerText := strings.ToLower(text)
+		return func(value string) bool {
+			return strings.Contains(strings.ToLower(value), lowerText)
+		}, nil
+	}
+
+	re, err := regexp.Compile("(?i)" + text)
+	if err != nil {
+		return nil, err
+	}
+
+	return re.MatchString, nil
+}
+
+// eventMatches reports whether any of the given properties of the event matches
+func eventMatches(event *model.CalendarObject, fields []string, matches func(string) bool) bool {
+	for _, field := range fields {
+		var value string
+		switch field {
+		case ical.PropSummary:
+			value = event.Summary
+		case ical.PropDescription:
+			value = event.Description
+		case ical.PropLocation:
+			value = event.Location
+		}
+
+		if matches(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchCalendar sends a text-match query per property, since the prop filters of a query must all match.
+// The results are merged by path. Without fields, all the events between from and to are returned
+func searchCalendar(server *model.CalendarServer, calendar *model.Calendar, fields []string, text string, from time.Time, to time.Time) ([]caldav.CalendarObject, error) {
+
+	queries := make([]backend.Query, 0, len(fields))
+	for _, field := range fields {
+		queries = append(queries, backend.Query{
+			Component: ical.CompEvent,
+			Start:     from,
+			End:       to,
+			Filters:   []dav.PropFilter{{Name: field, Text: text}},
+		})
+	}
+	if len(queries) == 0 {
+		queries = append(queries, backend.Query{Component: ical.CompEvent, Start: from, End: to})
+	}
+
+	seen := make(map[string]bool)
+	objects := make([]caldav.CalendarObject, 0)
+	for _, query := range queries {
+		queryObjects, err := queryCalendarObjects(server, calendar, query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range queryObjects {
+			if !seen[object.Path] {
+				seen[object.Path] = true
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	return objects, nil
+}
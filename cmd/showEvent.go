@@ -0,0 +1,85 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// eventShowCmd represents the event show command
+var eventShowCmd = &cobra.Command{
+	Use:   "show [uid]",
+	Short: "Shows the details of an event",
+	Long: `
+Shows the details of the event with the given UID: time, location, status, organizer, attendees and description.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		found, err := findEvent(args[0])
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		event := found.event
+		_, _ = event.Calendar.Color.Println(event.Summary)
+
+		when := formatEventTime(event)
+		if event.End != nil && !event.AllDay {
+			when += " - " + event.End.Format("15:04")
+		}
+		if event.Recurrence != nil {
+			when += " (recurrent)"
+		}
+
+		fmt.Println("When:", when)
+		if event.Location != "" {
+			fmt.Println("Where:", event.Location)
+		}
+		fmt.Println("Calendar:", event.Calendar.Name)
+		if event.Status != "" {
+			fmt.Println("Status:", strings.ToLower(event.Status))
+		}
+		if len(event.Categories) > 0 {
+			fmt.Println("Categories:", strings.Join(event.Categories, ", "))
+		}
+		if event.URL != "" {
+			fmt.Println("URL:", event.URL)
+		}
+		if event.Organizer != nil {
+			fmt.Println("Organizer:", event.Organizer)
+		}
+		if len(event.Attendees) > 0 {
+			fmt.Println("Attendees:")
+			for _, attendee := range event.Attendees {
+				fmt.Printf("  %s (%s)\n", attendee, strings.ToLower(attendee.PartStat))
+			}
+		}
+		fmt.Println("UID:", event.UID)
+
+		if event.Description != "" {
+			fmt.Println()
+			fmt.Println(event.Description)
+		}
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(eventShowCmd)
+}
@@ -0,0 +1,57 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// journalShowCmd represents the journal show command
+var journalShowCmd = &cobra.Command{
+	Use:   "show [uid]",
+	Short: "Shows a journal entry",
+	Long: `
+Shows the full body of the journal entry with the given UID. The UID is shown by the "journal list" command.
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		for _, journal := range fetchJournals(time.Time{}, time.Time{}) {
+			if journal.UID != args[0] {
+				continue
+			}
+
+			fmt.Println("Calendar:", journal.Calendar.Name)
+			if journal.Date != nil {
+				fmt.Println("Date:", journal.Date.Format("Monday, 2 January 2006"))
+			}
+			if journal.Summary != "" {
+				fmt.Println("Summary:", journal.Summary)
+			}
+			fmt.Println()
+			fmt.Println(journal.Description)
+			return
+		}
+
+		log.Printf("journal entry '%s' not found", args[0])
+	},
+}
+
+func init() {
+	journalCmd.AddCommand(journalShowCmd)
+}
@@ -0,0 +1,179 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+	"tsundoku.dev/quickcal/queue"
+)
+
+// the ways to resolve a conflict between a queued change and the server
+const (
+	resolutionOverwrite = iota
+	resolutionDiscard
+	resolutionLater
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sends the queued changes and syncs the cache",
+	Long: `
+Sends the changes that were queued while offline, and syncs the local cache of all the calendars.
+
+When a queued change conflicts with a change made on the server in the meantime, you are asked whether to overwrite
+the server version, to discard the queued change, or to keep it queued and decide later.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't sync in offline mode")
+			return
+		}
+
+		replayQueue()
+
+		for _, caldavServer := range caldavServers {
+			caldavServer := caldavServer
+
+			for i := range caldavServer.Calendars {
+				calendar := &caldavServer.Calendars[i]
+
+				if _, err := cachedCalendarObjects(&caldavServer, calendar); err != nil {
+					log.Printf("failed to sync calendar '%s': %v", calendar.Name, err)
+					continue
+				}
+
+				fmt.Printf("Synced calendar '%s'\n", calendar.Name)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+}
+
+// replayQueue sends the queued changes, in order. The changes of unreachable servers stay queued
+func replayQueue() {
+
+	ops, err := queue.Load()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if len(ops) == 0 {
+		return
+	}
+
+	remaining := make([]*queue.Operation, 0)
+	unreachable := make(map[string]bool)
+	for _, op := range ops {
+		server, ok := caldavServers[op.Server]
+		if !ok {
+			log.Printf("server '%s' of a queued %s is not configured", op.Server, op.Action)
+			remaining = append(remaining, op)
+			continue
+		}
+
+		// later changes of an unreachable server would be sent out of order
+		if unreachable[op.Server] {
+			remaining = append(remaining, op)
+			continue
+		}
+
+		err := sendOperation(&server, op, false)
+		if backend.IsConflict(err) {
+			switch askResolution(op) {
+			case resolutionOverwrite:
+				err = sendOperation(&server, op, true)
+			case resolutionDiscard:
+				fmt.Printf("Discarded queued %s: %s\n", op.Action, op.Summary())
+				continue
+			default:
+				remaining = append(remaining, op)
+				continue
+			}
+		}
+
+		if err != nil {
+			if isUnreachable(err) {
+				unreachable[op.Server] = true
+			}
+			log.Printf("failed to send the queued %s of '%s': %v", op.Action, op.Summary(), err)
+			remaining = append(remaining, op)
+			continue
+		}
+
+		fmt.Printf("Sent queued %s: %s\n", op.Action, op.Summary())
+	}
+
+	if err := queue.Save(remaining); err != nil {
+		log.Println(err)
+	}
+}
+
+// sendOperation sends a queued change. With force, the change is applied even if the object changed on the server
+func sendOperation(server *model.CalendarServer, op *queue.Operation, force bool) error {
+
+	etag := op.ETag
+	if force {
+		etag = ""
+	}
+
+	if op.Action == queue.ActionDelete {
+		return server.Backend.Delete(op.Path, etag)
+	}
+
+	cal, err := op.Decode()
+	if err != nil {
+		return err
+	}
+
+	if op.Action == queue.ActionCreate && !force {
+		_, err = server.Backend.Create(op.Path, cal)
+		return err
+	}
+
+	_, err = server.Backend.Update(op.Path, cal, etag)
+	return err
+}
+
+// askResolution asks how to resolve a conflict between a queued change and the server. Without a terminal, the
+// change stays queued
+func askResolution(op *queue.Operation) int {
+
+	overwrite := "Overwrite the server version with the queued change"
+	if op.Action == queue.ActionDelete {
+		overwrite = "Delete it anyway"
+	}
+
+	resolutionPrompt := promptui.Select{
+		Label: fmt.Sprintf("'%s' was modified on the server since the %s was queued", op.Summary(), op.Action),
+		Items: []string{overwrite, "Keep the server version and discard the queued change", "Decide later"},
+	}
+
+	index, _, err := resolutionPrompt.Run()
+	if err != nil {
+		return resolutionLater
+	}
+
+	return index
+}
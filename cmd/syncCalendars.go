@@ -0,0 +1,149 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"tsundoku.dev/quickcal/config"
+	"tsundoku.dev/quickcal/twoway"
+)
+
+var syncCalendarsCmdFlagDryRun bool
+
+// syncActionsDone describes the changes written by a sync
+var syncActionsDone = map[string]string{
+	twoway.ActionCreate: "Created",
+	twoway.ActionUpdate: "Updated",
+	twoway.ActionDelete: "Deleted",
+}
+
+// syncCalendarsCmd represents the calendar sync command
+var syncCalendarsCmd = &cobra.Command{
+	Use:   "sync [pair...]",
+	Short: "Syncs pairs of calendars in both directions",
+	Long: `
+Syncs the pairs of calendars of the configuration file, or only the given ones, in both directions. The calendars can
+be on different servers:
+
+sync:
+  - name: work-personal
+    a: Work
+    b: Personal
+    conflict: newest
+
+The events, tasks and journal entries created, modified or deleted in one calendar since the last sync are created,
+modified or deleted in the other one. When an object changed in both calendars, "conflict" decides which version wins:
+"newest" (default) keeps the one with the highest SEQUENCE, or else the latest LAST-MODIFIED, "a" or "b" always keep
+the version of that calendar, and "skip" leaves both and reports the conflict.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if offline {
+			log.Println("can't sync calendars in offline mode")
+			return
+		}
+
+		if len(cfg.Sync) == 0 {
+			log.Println("no calendar pairs found, add them to the sync section of the configuration file")
+			return
+		}
+
+		syncPairs := cfg.Sync
+		if len(args) > 0 {
+			syncPairs = make([]config.SyncPair, 0, len(args))
+			for _, name := range args {
+				syncPair := findSyncPair(name)
+				if syncPair == nil {
+					log.Printf("calendar pair '%s' not found", name)
+					return
+				}
+				syncPairs = append(syncPairs, *syncPair)
+			}
+		}
+
+		for _, syncPair := range syncPairs {
+			if err := syncCalendarPair(syncPair); err != nil {
+				log.Printf("failed to sync '%s': %v", syncPair.Name, err)
+			}
+		}
+	},
+}
+
+func init() {
+	calendarCmd.AddCommand(syncCalendarsCmd)
+
+	syncCalendarsCmd.Flags().BoolVar(&syncCalendarsCmdFlagDryRun, "dry-run", false, "Only show what would be changed")
+}
+
+// syncCalendarPair syncs the calendars of a pair, and prints the changes
+func syncCalendarPair(syncPair config.SyncPair) error {
+
+	sides := [2]twoway.Side{}
+	for i, name := range []string{syncPair.A, syncPair.B} {
+		server, calendar, err := findCalendar(name)
+		if err != nil {
+			return err
+		}
+		if err := checkWritable(calendar); err != nil {
+			return err
+		}
+
+		sides[i] = twoway.Side{Name: calendar.Name, Backend: server.Backend, Path: calendar.Path}
+	}
+
+	state, err := twoway.LoadState(syncPair.Name)
+	if err != nil {
+		return err
+	}
+
+	result, err := twoway.Sync(sides[0], sides[1], state, syncPair.Conflict, syncCalendarsCmdFlagDryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range result.Changes {
+		if syncCalendarsCmdFlagDryRun {
+			fmt.Printf("Would %s '%s' in '%s'\n", change.Action, change.UID, change.Side)
+		} else {
+			fmt.Printf("%s '%s' in '%s'\n", syncActionsDone[change.Action], change.UID, change.Side)
+		}
+	}
+	for _, conflict := range result.Conflicts {
+		fmt.Printf("Conflict: %s\n", conflict)
+	}
+	for _, err := range result.Errors {
+		log.Println(err)
+	}
+
+	fmt.Printf("Synced '%s': %d changes, %d conflicts, %d errors\n", syncPair.Name, len(result.Changes), len(result.Conflicts), len(result.Errors))
+
+	if syncCalendarsCmdFlagDryRun {
+		return nil
+	}
+
+	return state.Save()
+}
+
+// findSyncPair returns the pair of calendars with the given name
+func findSyncPair(name string) *config.SyncPair {
+	for i := range cfg.Sync {
+		if cfg.Sync[i].Name == name {
+			return &cfg.Sync[i]
+		}
+	}
+	return nil
+}
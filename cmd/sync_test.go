@@ -0,0 +1,90 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/model"
+	"tsundoku.dev/quickcal/queue"
+)
+
+func TestReplayQueue(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	memory.Unreachable = true
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// an event created and then modified offline is only created once
+	event := newTestEvent("planning", "Planning", start)
+	if _, err := createEvent("", event); err != nil {
+		t.Fatal(err)
+	}
+	event.Props.SetText(ical.PropSummary, "Planning v2")
+	server := caldavServers["memory"]
+	if _, err := updateObject(&server, &server.Calendars[0], "/personal/planning.ics", newCalendar(event), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing is sent while the server is unreachable
+	replayQueue()
+	if ops, err := queue.Load(); err != nil || len(ops) != 1 {
+		t.Fatalf("got queue %v, error %v", ops, err)
+	}
+
+	memory.Unreachable = false
+	replayQueue()
+
+	ops, err := queue.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) != 0 {
+		t.Fatalf("the queue wasn't emptied: %v", ops)
+	}
+
+	object, err := memory.Object("/personal/planning.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := model.PropValue(object.Data.Children[0], ical.PropSummary); got != "Planning v2" {
+		t.Fatalf("got summary %q", got)
+	}
+}
+
+func TestReplayQueueDelete(t *testing.T) {
+
+	memory := setupMemoryServer(t)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	etag, err := createEvent("", newTestEvent("planning", "Planning", start))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	memory.Unreachable = true
+	server := caldavServers["memory"]
+	if err := deleteObject(&server, &server.Calendars[0], "/personal/planning.ics", etag); err != nil {
+		t.Fatal(err)
+	}
+
+	memory.Unreachable = false
+	replayQueue()
+
+	if _, err := memory.Object("/personal/planning.ics"); err == nil {
+		t.Fatal("the queued deletion wasn't sent")
+	}
+}
@@ -0,0 +1,101 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+	"tsundoku.dev/quickcal/queue"
+)
+
+// createObject uploads a new calendar object, and returns its ETag. In offline mode, or when the server is
+// unreachable, the creation is queued and the ETag is empty
+func createObject(server *model.CalendarServer, calendar *model.Calendar, path string, cal *ical.Calendar) (string, error) {
+
+	if err := checkWritable(calendar); err != nil {
+		return "", err
+	}
+
+	if !offline {
+		etag, err := server.Backend.Create(path, cal)
+		if err == nil || !isUnreachable(err) {
+			return etag, err
+		}
+	}
+
+	return "", queueOperation(queue.ActionCreate, server, calendar, path, "", cal)
+}
+
+// updateObject replaces a calendar object if its ETag still matches, and returns the new ETag. In offline mode, or
+// when the server is unreachable, the update is queued and the ETag is empty
+func updateObject(server *model.CalendarServer, calendar *model.Calendar, path string, cal *ical.Calendar, etag string) (string, error) {
+
+	if err := checkWritable(calendar); err != nil {
+		return "", err
+	}
+
+	if !offline {
+		newETag, err := server.Backend.Update(path, cal, etag)
+		if err == nil || !isUnreachable(err) {
+			return newETag, err
+		}
+	}
+
+	return "", queueOperation(queue.ActionUpdate, server, calendar, path, etag, cal)
+}
+
+// deleteObject deletes a calendar object if its ETag still matches. In offline mode, or when the server is
+// unreachable, the deletion is queued
+func deleteObject(server *model.CalendarServer, calendar *model.Calendar, path string, etag string) error {
+
+	if err := checkWritable(calendar); err != nil {
+		return err
+	}
+
+	if !offline {
+		err := server.Backend.Delete(path, etag)
+		if err == nil || !isUnreachable(err) {
+			return err
+		}
+	}
+
+	return queueOperation(queue.ActionDelete, server, calendar, path, etag, nil)
+}
+
+// checkWritable returns an error for read-only calendars, so their changes are neither sent nor queued
+func checkWritable(calendar *model.Calendar) error {
+	if calendar.ReadOnly {
+		return fmt.Errorf("calendar '%s' is a read-only feed: %w", calendar.Name, backend.ErrReadOnly)
+	}
+	return nil
+}
+
+func queueOperation(action string, server *model.CalendarServer, calendar *model.Calendar, path string, etag string, cal *ical.Calendar) error {
+
+	op, err := queue.NewOperation(action, server.Name, calendar.Name, path, etag, cal)
+	if err != nil {
+		return err
+	}
+
+	if err := queue.Add(op); err != nil {
+		return err
+	}
+
+	log.Printf("server '%s' is not available, the %s was queued. Run 'qc sync' to send it", server.Name, action)
+	return nil
+}
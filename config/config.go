@@ -13,23 +13,55 @@ You should have received a copy of the GNU General Public License along with Qui
 
 package config
 
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
 type Calendar struct {
-	Name    string
-	Path    string
-	Color   string
-	Default bool
+	Name            string
+	Path            string
+	Color           string
+	Default         bool
+	Components      []string
+	RejectConflicts bool `mapstructure:"rejectConflicts"`
 }
 type Server struct {
-	Name      string      `mapstructure:"name"`
-	URL       string      `mapstructure:"url"`
-	User      string      `mapstructure:"user"`
-	Password  string      `mapstructure:"password"`
-	Calendars []*Calendar `mapstructure:"calendars"`
+	Name        string      `mapstructure:"name"`
+	Type        string      `mapstructure:"type"` // "caldav" (default), "vdir" or "ics"
+	Path        string      `mapstructure:"path"` // the directory of a vdir
+	URL         string      `mapstructure:"url"`  // the server, or the http(s), webcal or file URL of an ics feed
+	User        string      `mapstructure:"user"`
+	Password    string      `mapstructure:"password"`
+	Email       string      `mapstructure:"email"`
+	DisplayName string      `mapstructure:"displayName"` // written in ORGANIZER when sending invitations
+	Scheduling  string      `mapstructure:"scheduling"`  // "server" (default) or "email" to send iMIP emails
+	Sendmail    string      `mapstructure:"sendmail"`    // sendmail-compatible command for iMIP emails
+	Calendars   []*Calendar `mapstructure:"calendars"`
+}
+
+// Availability defines when meetings can be scheduled by the free command
+type Availability struct {
+	WorkStart string        `mapstructure:"workStart"`
+	WorkEnd   string        `mapstructure:"workEnd"`
+	WorkDays  []string      `mapstructure:"workDays"`
+	Buffer    time.Duration `mapstructure:"buffer"`
+}
+
+// SyncPair is two calendars, by name or path, kept in sync in both directions by the calendar sync command
+type SyncPair struct {
+	Name     string `mapstructure:"name"`
+	A        string `mapstructure:"a"`
+	B        string `mapstructure:"b"`
+	Conflict string `mapstructure:"conflict"` // "newest" (default), "a", "b" or "skip"
 }
 
 type Config struct {
-	Servers  []*Server `mapstructure:"servers"`
-	Timezone string    `mapstructure:"timezone"`
+	Servers      []*Server    `mapstructure:"servers"`
+	Timezone     string       `mapstructure:"timezone"`
+	Availability Availability `mapstructure:"availability"`
+	Sync         []SyncPair   `mapstructure:"sync"`
 }
 
 func GetServerByName(cfg *Config, name string) *Server {
@@ -40,3 +72,18 @@ func GetServerByName(cfg *Config, name string) *Server {
 	}
 	return nil
 }
+
+// StateDir returns the quickcal directory under the XDG state directory, for the data that must survive cache cleanups
+func StateDir() (string, error) {
+
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+
+	return filepath.Join(dir, "quickcal"), nil
+}
@@ -14,8 +14,9 @@ You should have received a copy of the GNU General Public License along with Qui
 package constants
 
 const (
-	TimeLayoutICalDateTime  = "20060102T150405"
-	TimeLayoutICalDate      = "20060102"
-	TimeLayoutInputDate     = "02/01/2006"
-	TimeLayoutInputDateTime = "02/01/2006 15:04"
+	TimeLayoutICalDateTime    = "20060102T150405"
+	TimeLayoutICalDateTimeUTC = "20060102T150405Z"
+	TimeLayoutICalDate        = "20060102"
+	TimeLayoutInputDate       = "02/01/2006"
+	TimeLayoutInputDateTime   = "02/01/2006 15:04"
 )
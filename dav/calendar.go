@@ -0,0 +1,33 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package dav
+
+// CalendarColors returns the Apple calendar-color property of the calendars in the home set, by calendar path.
+// Calendars without a color are not included
+func (c *Client) CalendarColors(calendarHomeSet string) (map[string]string, error) {
+
+	ms, err := c.PropFind(calendarHomeSet, "1", CalendarColorName)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := make(map[string]string)
+	for _, resp := range ms.Responses {
+		if calendarColor := resp.Prop(CalendarColorName); calendarColor != "" {
+			colors[resp.Path] = calendarColor
+		}
+	}
+
+	return colors, nil
+}
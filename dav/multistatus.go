@@ -0,0 +1,186 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package dav
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	CurrentUserPrincipalName = xml.Name{Space: "DAV:", Local: "current-user-principal"}
+	DisplayNameName          = xml.Name{Space: "DAV:", Local: "displayname"}
+	CalendarColorName        = xml.Name{Space: "http://apple.com/ns/ical/", Local: "calendar-color"}
+	GetETagName              = xml.Name{Space: "DAV:", Local: "getetag"}
+	CalendarDataName         = xml.Name{Space: caldavNamespace, Local: "calendar-data"}
+	GetCTagName              = xml.Name{Space: "http://calendarserver.org/ns/", Local: "getctag"}
+	SyncTokenName            = xml.Name{Space: "DAV:", Local: "sync-token"}
+
+	ScheduleInboxURLName       = xml.Name{Space: caldavNamespace, Local: "schedule-inbox-URL"}
+	ScheduleOutboxURLName      = xml.Name{Space: caldavNamespace, Local: "schedule-outbox-URL"}
+	CalendarUserAddressSetName = xml.Name{Space: caldavNamespace, Local: "calendar-user-address-set"}
+)
+
+const caldavNamespace = "urn:ietf:params:xml:ns:caldav"
+
+// MultiStatus is a decoded multi-status response
+type MultiStatus struct {
+	Responses []*Response
+	// SyncToken is the new sync token of a sync-collection REPORT
+	SyncToken string
+}
+
+// Response is a single resource of a multi-status response
+type Response struct {
+	Path string
+	// Status is the status of the resource when it has no properties, e.g. 404 for a deleted member
+	Status int
+	// Props contains the properties that were found, by name
+	Props map[xml.Name]*Property
+}
+
+// Property is a raw WebDAV property
+type Property struct {
+	XMLName xml.Name
+	Text    string `xml:",chardata"`
+	Inner   []byte `xml:",innerxml"`
+}
+
+// Prop returns the text value of a property, or an empty string if it wasn't found
+func (r *Response) Prop(name xml.Name) string {
+	prop, ok := r.Props[name]
+	if !ok {
+		return ""
+	}
+
+	return strings.TrimSpace(prop.Text)
+}
+
+// Href returns the path of the first href contained in a property, e.g. current-user-principal
+func (r *Response) Href(name xml.Name) string {
+	hrefs := r.Hrefs(name)
+	if len(hrefs) == 0 {
+		return ""
+	}
+
+	return hrefPath(hrefs[0])
+}
+
+// Hrefs returns the raw hrefs contained in a property, e.g. the mailto: addresses of calendar-user-address-set
+func (r *Response) Hrefs(name xml.Name) []string {
+	prop, ok := r.Props[name]
+	if !ok {
+		return nil
+	}
+
+	var hrefs struct {
+		Hrefs []string `xml:"DAV: href"`
+	}
+	if err := xml.Unmarshal([]byte("<p>"+string(prop.Inner)+"</p>"), &hrefs); err != nil {
+		return nil
+	}
+
+	for i := range hrefs.Hrefs {
+		hrefs.Hrefs[i] = strings.TrimSpace(hrefs.Hrefs[i])
+	}
+
+	return hrefs.Hrefs
+}
+
+type emptyElement struct {
+	XMLName xml.Name
+}
+
+type propFind struct {
+	XMLName xml.Name `xml:"DAV: propfind"`
+	Prop    struct {
+		Names []emptyElement
+	} `xml:"DAV: prop"`
+}
+
+type multiStatus struct {
+	XMLName   xml.Name   `xml:"DAV: multistatus"`
+	Responses []response `xml:"DAV: response"`
+	SyncToken string     `xml:"DAV: sync-token"`
+}
+
+type response struct {
+	Hrefs     []string   `xml:"DAV: href"`
+	Status    string     `xml:"DAV: status"`
+	PropStats []propStat `xml:"DAV: propstat"`
+}
+
+type propStat struct {
+	Prop struct {
+		Props []*Property `xml:",any"`
+	} `xml:"DAV: prop"`
+	Status string `xml:"DAV: status"`
+}
+
+func (ms *multiStatus) decode() (*MultiStatus, error) {
+
+	decoded := &MultiStatus{SyncToken: strings.TrimSpace(ms.SyncToken)}
+	for _, resp := range ms.Responses {
+		if len(resp.Hrefs) == 0 {
+			continue
+		}
+
+		r := &Response{
+			Path:   hrefPath(resp.Hrefs[0]),
+			Status: parseStatus(resp.Status),
+			Props:  make(map[xml.Name]*Property),
+		}
+
+		for _, propStat := range resp.PropStats {
+			if parseStatus(propStat.Status)/100 != 2 {
+				continue
+			}
+
+			for _, prop := range propStat.Prop.Props {
+				r.Props[prop.XMLName] = prop
+			}
+		}
+
+		decoded.Responses = append(decoded.Responses, r)
+	}
+
+	return decoded, nil
+}
+
+// parseStatus returns the code of a status line such as "HTTP/1.1 200 OK", or 0 if it can't be parsed
+func parseStatus(status string) int {
+	fields := strings.Fields(status)
+	if len(fields) < 2 {
+		return 0
+	}
+
+	code, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0
+	}
+
+	return code
+}
+
+// hrefPath returns the decoded path of an href, which can be a full URL or a path
+func hrefPath(href string) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return strings.TrimSpace(href)
+	}
+
+	return u.Path
+}
@@ -0,0 +1,136 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package dav
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
+// IsPreconditionFailed reports whether a conditional request failed because the object was modified on the server,
+// or because it already exists
+func IsPreconditionFailed(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusPreconditionFailed
+}
+
+// CreateCalendarObject uploads a new calendar object, failing if it already exists.
+// It returns the ETag of the object, when the server sends it
+func (c *Client) CreateCalendarObject(path string, cal *ical.Calendar) (string, error) {
+	return c.putCalendarObject(path, cal, "If-None-Match", "*")
+}
+
+// UpdateCalendarObject replaces a calendar object, only if its ETag still matches. Without an ETag, the object is
+// replaced unconditionally. It returns the new ETag of the object, when the server sends it
+func (c *Client) UpdateCalendarObject(path string, cal *ical.Calendar, etag string) (string, error) {
+	if etag == "" {
+		return c.putCalendarObject(path, cal, "", "")
+	}
+
+	return c.putCalendarObject(path, cal, "If-Match", quoteETag(etag))
+}
+
+func (c *Client) putCalendarObject(path string, cal *ical.Calendar, conditionHeader string, conditionValue string) (string, error) {
+
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return "", err
+	}
+
+	req, err := c.NewRequest(http.MethodPut, path, &buf)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", ical.MIMEType)
+	if conditionHeader != "" {
+		req.Header.Set(conditionHeader, conditionValue)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return unquoteETag(resp.Header.Get("ETag")), nil
+}
+
+// quoteETag returns the header value of an ETag. Opaque tags, as unquoteETag returns them, are quoted, and weak or
+// already quoted ETags are sent as they were received
+func quoteETag(etag string) string {
+	if strings.HasPrefix(etag, "W/") || strings.HasPrefix(etag, `"`) {
+		return etag
+	}
+
+	return `"` + etag + `"`
+}
+
+// unquoteETag removes the quotes of an ETag header, weak ETags are returned as they are
+func unquoteETag(etag string) string {
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		return etag
+	}
+
+	return unquoted
+}
+
+// DeleteCalendarObject deletes a calendar object, only if its ETag still matches. Without an ETag, the object is
+// deleted unconditionally
+func (c *Client) DeleteCalendarObject(path string, etag string) error {
+
+	req, err := c.NewRequest(http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", quoteETag(etag))
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// MoveCalendarObject moves a calendar object to another path of the server, e.g. another calendar, failing if the
+// destination exists. The object is only moved if its ETag still matches, when one is given
+func (c *Client) MoveCalendarObject(path string, destination string, etag string) error {
+
+	req, err := c.NewRequest("MOVE", path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Destination", c.ResolveHref(destination).String())
+	req.Header.Set("Overwrite", "F")
+	if etag != "" {
+		req.Header.Set("If-Match", quoteETag(etag))
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,122 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package dav
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/constants"
+)
+
+// PropFilter restricts a calendar query to the components with a property containing a text, case-insensitively.
+// With Negate, the components must not contain it
+type PropFilter struct {
+	Name   string
+	Text   string
+	Negate bool
+}
+
+type calendarQuery struct {
+	XMLName xml.Name `xml:"urn:ietf:params:xml:ns:caldav calendar-query"`
+	Prop    struct {
+		Names []emptyElement
+	} `xml:"DAV: prop"`
+	Filter struct {
+		CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	TimeRange   *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range,omitempty"`
+	PropFilters []propFilter `xml:"urn:ietf:params:xml:ns:caldav prop-filter,omitempty"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter,omitempty"`
+}
+
+type propFilter struct {
+	Name      string    `xml:"name,attr"`
+	TextMatch textMatch `xml:"urn:ietf:params:xml:ns:caldav text-match"`
+}
+
+type textMatch struct {
+	Text            string `xml:",chardata"`
+	NegateCondition string `xml:"negate-condition,attr,omitempty"`
+}
+
+// QueryCalendar sends a calendar-query REPORT for the objects with a component of the given type in the time range,
+// or at any time if start and end are zero, that match all the prop filters. The prop filters are ignored by
+// github.com/emersion/go-webdav/caldav, so they are sent here. Servers that don't support a filter answer with an error
+func (c *Client) QueryCalendar(calendar string, component string, start time.Time, end time.Time, filters []PropFilter) ([]caldav.CalendarObject, error) {
+
+	comp := compFilter{Name: component}
+	if !start.IsZero() || !end.IsZero() {
+		comp.TimeRange = &timeRange{
+			Start: start.UTC().Format(constants.TimeLayoutICalDateTimeUTC),
+			End:   end.UTC().Format(constants.TimeLayoutICalDateTimeUTC),
+		}
+	}
+	for _, filter := range filters {
+		match := textMatch{Text: filter.Text}
+		if filter.Negate {
+			match.NegateCondition = "yes"
+		}
+		comp.PropFilters = append(comp.PropFilters, propFilter{Name: filter.Name, TextMatch: match})
+	}
+
+	query := calendarQuery{}
+	query.Prop.Names = []emptyElement{{XMLName: GetETagName}, {XMLName: CalendarDataName}}
+	query.Filter.CompFilter = compFilter{Name: ical.CompCalendar, CompFilters: []compFilter{comp}}
+
+	req, err := c.NewXMLRequest("REPORT", calendar, &query)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+
+	ms, err := c.DoMultiStatus(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendarObjects(ms)
+}
+
+// calendarObjects decodes the calendar data of the responses of a calendar-query or calendar-multiget REPORT
+func calendarObjects(ms *MultiStatus) ([]caldav.CalendarObject, error) {
+
+	objects := make([]caldav.CalendarObject, 0, len(ms.Responses))
+	for _, resp := range ms.Responses {
+		data := resp.Prop(CalendarDataName)
+		if data == "" {
+			continue
+		}
+
+		cal, err := ical.NewDecoder(strings.NewReader(data)).Decode()
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, caldav.CalendarObject{
+			Path: resp.Path,
+			ETag: unquoteETag(resp.Prop(GetETagName)),
+			Data: cal,
+		})
+	}
+
+	return objects, nil
+}
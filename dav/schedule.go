@@ -0,0 +1,163 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package dav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/constants"
+)
+
+// Scheduling contains the RFC 6638 scheduling properties of a principal
+type Scheduling struct {
+	InboxURL  string
+	OutboxURL string
+	// Addresses are the calendar user addresses of the principal, usually mailto: URIs
+	Addresses []string
+}
+
+// ScheduleResponse is the answer of the server for one of the recipients of a scheduling message
+type ScheduleResponse struct {
+	Recipient     string
+	RequestStatus string
+	Data          *ical.Calendar
+}
+
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+type freeBusyQuery struct {
+	XMLName   xml.Name  `xml:"urn:ietf:params:xml:ns:caldav free-busy-query"`
+	TimeRange timeRange `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+}
+
+type scheduleResponse struct {
+	XMLName   xml.Name `xml:"urn:ietf:params:xml:ns:caldav schedule-response"`
+	Responses []struct {
+		Recipient struct {
+			Href string `xml:"DAV: href"`
+		} `xml:"urn:ietf:params:xml:ns:caldav recipient"`
+		RequestStatus string `xml:"urn:ietf:params:xml:ns:caldav request-status"`
+		CalendarData  string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+	} `xml:"urn:ietf:params:xml:ns:caldav response"`
+}
+
+// SchedulingInfo returns the scheduling inbox, outbox and addresses of a principal.
+// The inbox and outbox are empty if the server doesn't support RFC 6638 scheduling
+func (c *Client) SchedulingInfo(principal string) (*Scheduling, error) {
+
+	ms, err := c.PropFind(principal, "0", ScheduleInboxURLName, ScheduleOutboxURLName, CalendarUserAddressSetName)
+	if err != nil {
+		return nil, err
+	}
+	if len(ms.Responses) == 0 {
+		return nil, fmt.Errorf("no properties found for principal '%s'", principal)
+	}
+
+	resp := ms.Responses[0]
+	return &Scheduling{
+		InboxURL:  resp.Href(ScheduleInboxURLName),
+		OutboxURL: resp.Href(ScheduleOutboxURLName),
+		Addresses: resp.Hrefs(CalendarUserAddressSetName),
+	}, nil
+}
+
+// MailtoAddress returns the first mailto: address of the principal, without the scheme
+func (s *Scheduling) MailtoAddress() string {
+	for _, address := range s.Addresses {
+		if strings.HasPrefix(strings.ToLower(address), "mailto:") {
+			return address[len("mailto:"):]
+		}
+	}
+
+	return ""
+}
+
+// FreeBusyQuery sends a CALDAV:free-busy-query REPORT to a calendar, and returns the VFREEBUSY answer
+func (c *Client) FreeBusyQuery(calendar string, start time.Time, end time.Time) (*ical.Calendar, error) {
+
+	query := freeBusyQuery{
+		TimeRange: timeRange{
+			Start: start.UTC().Format(constants.TimeLayoutICalDateTimeUTC),
+			End:   end.UTC().Format(constants.TimeLayoutICalDateTimeUTC),
+		},
+	}
+
+	req, err := c.NewXMLRequest("REPORT", calendar, &query)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ical.NewDecoder(resp.Body).Decode()
+}
+
+// PostOutbox sends a scheduling message, such as a VFREEBUSY request, to the scheduling outbox
+func (c *Client) PostOutbox(outbox string, cal *ical.Calendar) ([]ScheduleResponse, error) {
+
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return nil, err
+	}
+
+	req, err := c.NewRequest(http.MethodPost, outbox, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", ical.MIMEType)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var decoded scheduleResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	responses := make([]ScheduleResponse, 0, len(decoded.Responses))
+	for _, r := range decoded.Responses {
+		response := ScheduleResponse{
+			Recipient:     strings.TrimSpace(r.Recipient.Href),
+			RequestStatus: strings.TrimSpace(r.RequestStatus),
+		}
+
+		if strings.TrimSpace(r.CalendarData) != "" {
+			response.Data, err = ical.NewDecoder(strings.NewReader(r.CalendarData)).Decode()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
@@ -0,0 +1,148 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package dav
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// SyncResult contains the changes of a collection since the previous sync token
+type SyncResult struct {
+	Token string
+	// Changed maps the path of the new and modified objects to their ETag
+	Changed map[string]string
+	Deleted []string
+}
+
+type syncCollection struct {
+	XMLName   xml.Name `xml:"DAV: sync-collection"`
+	SyncToken string   `xml:"DAV: sync-token"`
+	SyncLevel string   `xml:"DAV: sync-level"`
+	Prop      struct {
+		Names []emptyElement
+	} `xml:"DAV: prop"`
+}
+
+type calendarMultiget struct {
+	XMLName xml.Name `xml:"urn:ietf:params:xml:ns:caldav calendar-multiget"`
+	Prop    struct {
+		Names []emptyElement
+	} `xml:"DAV: prop"`
+	Hrefs []string `xml:"DAV: href"`
+}
+
+// SyncCollection sends an RFC 6578 sync-collection REPORT. An empty token returns every object of the collection.
+// Servers answer with an error when they don't support it, or when the token is no longer valid
+func (c *Client) SyncCollection(path string, token string) (*SyncResult, error) {
+
+	body := syncCollection{SyncToken: token, SyncLevel: "1"}
+	body.Prop.Names = []emptyElement{{XMLName: GetETagName}}
+
+	req, err := c.NewXMLRequest("REPORT", path, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "0")
+
+	ms, err := c.DoMultiStatus(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Token: ms.SyncToken, Changed: make(map[string]string)}
+	for _, resp := range ms.Responses {
+		if isCollection(resp.Path, path) {
+			continue
+		}
+
+		if resp.Status == http.StatusNotFound {
+			result.Deleted = append(result.Deleted, resp.Path)
+			continue
+		}
+		result.Changed[resp.Path] = unquoteETag(resp.Prop(GetETagName))
+	}
+
+	return result, nil
+}
+
+// CollectionTags returns the getctag and sync-token of a collection. Either can be empty if not supported
+func (c *Client) CollectionTags(path string) (string, string, error) {
+
+	ms, err := c.PropFind(path, "0", GetCTagName, SyncTokenName)
+	if err != nil {
+		return "", "", err
+	}
+	if len(ms.Responses) == 0 {
+		return "", "", nil
+	}
+
+	return ms.Responses[0].Prop(GetCTagName), ms.Responses[0].Prop(SyncTokenName), nil
+}
+
+// ETags returns the ETag of every member of a collection, by path
+func (c *Client) ETags(path string) (map[string]string, error) {
+
+	ms, err := c.PropFind(path, "1", GetETagName)
+	if err != nil {
+		return nil, err
+	}
+
+	etags := make(map[string]string)
+	for _, resp := range ms.Responses {
+		if isCollection(resp.Path, path) {
+			continue
+		}
+		etags[resp.Path] = unquoteETag(resp.Prop(GetETagName))
+	}
+
+	return etags, nil
+}
+
+// MultiGet downloads the calendar objects with the given paths from a calendar, with a calendar-multiget REPORT
+func (c *Client) MultiGet(path string, paths []string) ([]caldav.CalendarObject, error) {
+
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	body := calendarMultiget{}
+	body.Prop.Names = []emptyElement{{XMLName: GetETagName}, {XMLName: CalendarDataName}}
+	for _, p := range paths {
+		body.Hrefs = append(body.Hrefs, (&url.URL{Path: p}).EscapedPath())
+	}
+
+	req, err := c.NewXMLRequest("REPORT", path, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+
+	ms, err := c.DoMultiStatus(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return calendarObjects(ms)
+}
+
+// isCollection reports whether a response path is the collection itself, which is included in depth 1 responses
+func isCollection(responsePath string, collectionPath string) bool {
+	return strings.TrimSuffix(responsePath, "/") == strings.TrimSuffix(collectionPath, "/") ||
+		strings.TrimSuffix(responsePath, "/") == strings.TrimSuffix(hrefPath(collectionPath), "/")
+}
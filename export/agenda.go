@@ -0,0 +1,110 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"tsundoku.dev/quickcal/model"
+)
+
+const agendaDayLayout = "Monday, 2 January 2006"
+
+// OrgAgenda writes expanded events as an org-mode agenda, with a heading per day and a sub-heading per event,
+// so the output can be added to the org-agenda files
+func OrgAgenda(w io.Writer, events []*model.CalendarObject) error {
+
+	return agenda(w, events, func(day string) string {
+		return fmt.Sprintf("* %s\n", day)
+	}, func(event *model.CalendarObject) string {
+		var ss strings.Builder
+
+		ss.WriteString(fmt.Sprintf("** %s\n   %s\n", oneLine(event.Summary), OrgTimestamp(event)))
+		if event.Location != "" {
+			ss.WriteString(fmt.Sprintf("   - Location: %s\n", oneLine(event.Location)))
+		}
+		if event.URL != "" {
+			ss.WriteString(fmt.Sprintf("   - Link: [[%s]]\n", event.URL))
+		}
+		ss.WriteString(fmt.Sprintf("   - Calendar: %s\n", event.Calendar.Name))
+
+		return ss.String()
+	})
+}
+
+// MarkdownAgenda writes expanded events as a markdown agenda, with a heading per day and a list item per event
+func MarkdownAgenda(w io.Writer, events []*model.CalendarObject) error {
+
+	return agenda(w, events, func(day string) string {
+		return fmt.Sprintf("## %s\n\n", day)
+	}, func(event *model.CalendarObject) string {
+		var ss strings.Builder
+
+		ss.WriteString(fmt.Sprintf("- **%s** %s", timeRange(event), oneLine(event.Summary)))
+		if event.Location != "" {
+			ss.WriteString(fmt.Sprintf(" — %s", oneLine(event.Location)))
+		}
+		if event.URL != "" {
+			ss.WriteString(fmt.Sprintf(" — [link](%s)", event.URL))
+		}
+		ss.WriteString(fmt.Sprintf(" _(%s)_\n", event.Calendar.Name))
+
+		return ss.String()
+	})
+}
+
+// agenda groups the events by their start day, writing a heading before the events of each day.
+// The events must be sorted by start time
+func agenda(w io.Writer, events []*model.CalendarObject, heading func(day string) string, item func(event *model.CalendarObject) string) error {
+
+	currentDay := ""
+	for _, event := range events {
+
+		day := event.Start.Format(agendaDayLayout)
+		if day != currentDay {
+			if currentDay != "" {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+
+			if _, err := io.WriteString(w, heading(day)); err != nil {
+				return err
+			}
+			currentDay = day
+		}
+
+		if _, err := io.WriteString(w, item(event)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// timeRange formats the start and end time of an event, e.g. 10:00-11:00, or "All day"
+func timeRange(event *model.CalendarObject) string {
+
+	if event.AllDay {
+		return "All day"
+	}
+
+	if event.End == nil || !event.End.After(*event.Start) {
+		return event.Start.Format("15:04")
+	}
+
+	return fmt.Sprintf("%s-%s", event.Start.Format("15:04"), event.End.Format("15:04"))
+}
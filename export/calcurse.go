@@ -0,0 +1,120 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"tsundoku.dev/quickcal/model"
+)
+
+const calcurseDateLayout = "01/02/2006"
+
+// Calcurse writes the events in the format of calcurse's apts file
+func Calcurse(w io.Writer, events []*model.CalendarObject, from time.Time, to time.Time) error {
+
+	for _, event := range events {
+
+		entries, err := occurrences(event, from, to, func(rule *rrule.ROption) bool {
+			return rule.Freq <= rrule.DAILY && !hasByRules(rule, *event.Start) && !multiDay(event)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			line, err := calcurseLine(entry)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func calcurseLine(event *model.CalendarObject) (string, error) {
+
+	var ss strings.Builder
+	start := *event.Start
+
+	if event.AllDay {
+		// [1] is the event type in calcurse
+		ss.WriteString(start.Format(calcurseDateLayout))
+		ss.WriteString(" [1]")
+
+		// a multi-day event repeats daily until its last day, recurrent ones are expanded before
+		if multiDay(event) && event.Recurrence == nil {
+			ss.WriteString(fmt.Sprintf(" {1D -> %s}", lastDay(event).Format(calcurseDateLayout)))
+		}
+	} else {
+		start, end := timedRange(event)
+		ss.WriteString(fmt.Sprintf("%s @ %s -> %s @ %s",
+			start.Format(calcurseDateLayout), start.Format("15:04"),
+			end.Format(calcurseDateLayout), end.Format("15:04")))
+	}
+
+	if event.Recurrence != nil {
+		types := map[rrule.Frequency]string{
+			rrule.DAILY:   "D",
+			rrule.WEEKLY:  "W",
+			rrule.MONTHLY: "M",
+			rrule.YEARLY:  "Y",
+		}
+
+		repetitionType, ok := types[event.Recurrence.Freq]
+		if !ok {
+			return "", fmt.Errorf("unsupported recurrence frequency: %v", event.Recurrence.Freq)
+		}
+
+		ss.WriteString(fmt.Sprintf(" {%d%s", interval(event.Recurrence), repetitionType))
+
+		untilDate, err := until(event)
+		if err != nil {
+			return "", err
+		}
+		if !event.AllDay {
+			untilDate = untilDate.In(Location)
+		}
+		if !untilDate.IsZero() {
+			ss.WriteString(" -> ")
+			ss.WriteString(untilDate.Format(calcurseDateLayout))
+		}
+
+		ss.WriteString("}")
+	}
+
+	// only appointments separate the summary with a pipe
+	if event.AllDay {
+		ss.WriteString(" ")
+	} else {
+		ss.WriteString(" |")
+	}
+	ss.WriteString(oneLine(event.Summary))
+
+	return ss.String(), nil
+}
+
+// multiDay reports whether an all-day event lasts more than one day
+func multiDay(event *model.CalendarObject) bool {
+	return event.AllDay && lastDay(event).After(*event.Start)
+}
@@ -0,0 +1,131 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"tsundoku.dev/quickcal/model"
+)
+
+const diaryDateLayout = "1/2/2006"
+
+// Diary writes the events as Emacs diary entries, using the default american date style
+func Diary(w io.Writer, events []*model.CalendarObject, from time.Time, to time.Time) error {
+
+	for _, event := range events {
+
+		// multi-day events are written as a block of days, so recurrent ones are expanded
+		entries, err := occurrences(event, from, to, func(rule *rrule.ROption) bool {
+			return calendarRecurrence(rule, *event.Start) && !multiDay(event)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			line, err := diaryLine(entry)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func diaryLine(event *model.CalendarObject) (string, error) {
+
+	var ss strings.Builder
+	start, end := *event.Start, *event.Start
+	if !event.AllDay {
+		start, end = timedRange(event)
+	}
+
+	rule := event.Recurrence
+	switch {
+	case rule == nil && multiDay(event):
+		ss.WriteString(fmt.Sprintf("%%%%(diary-block %s %s)", diarySexpDate(start), diarySexpDate(lastDay(event))))
+	case rule == nil:
+		ss.WriteString(start.Format(diaryDateLayout))
+	default:
+		conditions := make([]string, 0)
+
+		switch {
+		case rule.Freq == rrule.WEEKLY && len(rule.Byweekday) > 0:
+			// calendar-day-of-week starts on Sunday
+			days := make([]string, 0, len(rule.Byweekday))
+			for _, weekday := range rule.Byweekday {
+				days = append(days, fmt.Sprintf("%d", (weekday.Day()+1)%7))
+			}
+			conditions = append(conditions, fmt.Sprintf("(memq (calendar-day-of-week date) '(%s))", strings.Join(days, " ")))
+		case rule.Freq == rrule.DAILY:
+			conditions = append(conditions, fmt.Sprintf("(diary-cyclic %d %s)", interval(rule), diarySexpDate(start)))
+		case rule.Freq == rrule.WEEKLY:
+			conditions = append(conditions, fmt.Sprintf("(diary-cyclic %d %s)", 7*interval(rule), diarySexpDate(start)))
+		case rule.Freq == rrule.MONTHLY:
+			conditions = append(conditions, fmt.Sprintf("(diary-date t %d t)", start.Day()))
+		case rule.Freq == rrule.YEARLY:
+			conditions = append(conditions, fmt.Sprintf("(diary-date %d %d t)", start.Month(), start.Day()))
+		}
+
+		conditions = append(conditions, fmt.Sprintf("(>= (calendar-absolute-from-gregorian date) (calendar-absolute-from-gregorian '(%s)))", diarySexpDate(start)))
+
+		untilDate, err := until(event)
+		if err != nil {
+			return "", err
+		}
+		if !event.AllDay {
+			untilDate = untilDate.In(Location)
+		}
+		if !untilDate.IsZero() {
+			conditions = append(conditions, fmt.Sprintf("(<= (calendar-absolute-from-gregorian date) (calendar-absolute-from-gregorian '(%s)))", diarySexpDate(untilDate)))
+		}
+
+		ss.WriteString(fmt.Sprintf("%%%%(and %s)", strings.Join(conditions, " ")))
+	}
+
+	if !event.AllDay {
+		ss.WriteString(" ")
+		ss.WriteString(start.Format("15:04"))
+
+		if end.After(start) {
+			ss.WriteString("-")
+			ss.WriteString(end.Format("15:04"))
+		}
+	}
+
+	ss.WriteString(" ")
+	ss.WriteString(oneLine(event.Summary))
+	if event.Location != "" {
+		ss.WriteString(" (")
+		ss.WriteString(oneLine(event.Location))
+		ss.WriteString(")")
+	}
+
+	return ss.String(), nil
+}
+
+// diarySexpDate formats a date as the month, day and year arguments of the diary sexp functions
+func diarySexpDate(t time.Time) string {
+	return fmt.Sprintf("%d %d %d", t.Month(), t.Day(), t.Year())
+}
@@ -0,0 +1,141 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"tsundoku.dev/quickcal/model"
+)
+
+// Exporter writes a list of events in a specific format.
+// Recurrent events that can't be represented in the format are expanded between from and to
+type Exporter func(w io.Writer, events []*model.CalendarObject, from time.Time, to time.Time) error
+
+// Location is the time zone the times of timed events are written in, as the target formats have no time zones
+var Location = time.Local
+
+// Exporters maps the format names accepted by the export command to their exporter
+var Exporters = map[string]Exporter{
+	"remind":   Remind,
+	"calcurse": Calcurse,
+	"diary":    Diary,
+	"org":      Org,
+}
+
+// occurrences returns the event itself if it isn't recurrent or if its recurrence rule is supported,
+// otherwise the event is expanded between from and to
+func occurrences(event *model.CalendarObject, from time.Time, to time.Time, supported func(rule *rrule.ROption) bool) ([]*model.CalendarObject, error) {
+
+	if event.Recurrence == nil || supported(event.Recurrence) {
+		return []*model.CalendarObject{event}, nil
+	}
+
+	return event.Occurrences(from, to)
+}
+
+// hasByRules reports whether the recurrence rule uses any BY* part, other than a BYMONTHDAY that matches the start date
+func hasByRules(rule *rrule.ROption, start time.Time) bool {
+
+	if len(rule.Bymonthday) > 1 || (len(rule.Bymonthday) == 1 && rule.Bymonthday[0] != start.Day()) {
+		return true
+	}
+
+	return len(rule.Bysetpos) > 0 || len(rule.Bymonth) > 0 || len(rule.Byyearday) > 0 || len(rule.Byweekno) > 0 ||
+		len(rule.Byweekday) > 0 || len(rule.Byhour) > 0 || len(rule.Byminute) > 0 || len(rule.Bysecond) > 0 ||
+		len(rule.Byeaster) > 0
+}
+
+// calendarRecurrence reports whether the recurrence rule can be written with calendar-like repetitions:
+// every n days or weeks, on some weekdays of every week, or every month or year on the start date
+func calendarRecurrence(rule *rrule.ROption, start time.Time) bool {
+
+	if rule.Freq == rrule.WEEKLY && len(rule.Byweekday) > 0 {
+		weekdaysOnly := *rule
+		weekdaysOnly.Byweekday = nil
+		return interval(rule) == 1 && !hasByRules(&weekdaysOnly, start)
+	}
+
+	switch rule.Freq {
+	case rrule.DAILY, rrule.WEEKLY:
+		return !hasByRules(rule, start)
+	case rrule.MONTHLY, rrule.YEARLY:
+		return interval(rule) == 1 && !hasByRules(rule, start)
+	}
+
+	return false
+}
+
+// interval returns the interval of the recurrence rule, which defaults to 1
+func interval(rule *rrule.ROption) int {
+	if rule.Interval < 1 {
+		return 1
+	}
+
+	return rule.Interval
+}
+
+// until returns the last date of a recurrent event, either from UNTIL or from COUNT.
+// It returns a zero time if the event repeats forever
+func until(event *model.CalendarObject) (time.Time, error) {
+
+	rule := event.Recurrence
+	if !rule.Until.IsZero() || rule.Count == 0 {
+		return rule.Until, nil
+	}
+
+	rruleOption := *rule
+	rruleOption.Dtstart = *event.Start
+
+	rRule, err := rrule.NewRRule(rruleOption)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	all := rRule.All()
+	if len(all) == 0 {
+		return *event.Start, nil
+	}
+
+	return all[len(all)-1], nil
+}
+
+// lastDay returns the last day of an all-day event, as the end date is exclusive
+func lastDay(event *model.CalendarObject) time.Time {
+	if event.End == nil || !event.End.After(*event.Start) {
+		return *event.Start
+	}
+
+	return event.End.AddDate(0, 0, -1)
+}
+
+// timedRange returns the start and end of a timed event in Location. Events without an end, or ending before they
+// start, end at their start
+func timedRange(event *model.CalendarObject) (time.Time, time.Time) {
+
+	start := event.Start.In(Location)
+	if event.End == nil || !event.End.After(start) {
+		return start, start
+	}
+
+	return start, event.End.In(Location)
+}
+
+// oneLine replaces new lines so the text fits in a single line
+func oneLine(text string) string {
+	return strings.ReplaceAll(text, "\n", " ")
+}
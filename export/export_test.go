@@ -0,0 +1,85 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"tsundoku.dev/quickcal/model"
+)
+
+func newEvent(summary string, start time.Time, end time.Time, allDay bool) *model.CalendarObject {
+	return &model.CalendarObject{Summary: summary, Start: &start, End: &end, AllDay: allDay}
+}
+
+func TestExport(t *testing.T) {
+
+	previous := Location
+	Location = time.FixedZone("UTC+2", 2*60*60)
+	t.Cleanup(func() { Location = previous })
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	from, to := day, day.AddDate(0, 0, 7)
+
+	trip := newEvent("Trip", day, day.AddDate(0, 0, 3), true)
+	review := newEvent("Review", day.Add(9*time.Hour), day.Add(10*time.Hour), false)
+	late := newEvent("Late call", day.Add(23*time.Hour), day.Add(24*time.Hour), false)
+
+	// a weekly weekend that can't be written as a block with a repetition
+	weekend := newEvent("Weekend", day.AddDate(0, 0, -2), day, true)
+	weekend.Recurrence = &rrule.ROption{Freq: rrule.WEEKLY}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{format: "remind", want: []string{
+			"REM 2 Mar 2026 THROUGH 4 Mar 2026 MSG Trip",
+			"REM 2 Mar 2026 AT 11:00 DURATION 1:00 MSG Review",
+			"REM 3 Mar 2026 AT 01:00 DURATION 1:00 MSG Late call",
+			"REM 7 Mar 2026 THROUGH 8 Mar 2026 MSG Weekend",
+		}},
+		{format: "diary", want: []string{
+			"%%(diary-block 3 2 2026 3 4 2026) Trip",
+			"3/2/2026 11:00-12:00 Review",
+			"3/3/2026 01:00-02:00 Late call",
+			"%%(diary-block 3 7 2026 3 8 2026) Weekend",
+		}},
+		{format: "calcurse", want: []string{
+			"03/02/2026 [1] {1D -> 03/04/2026} Trip",
+			"03/02/2026 @ 11:00 -> 03/02/2026 @ 12:00 |Review",
+			"03/03/2026 @ 01:00 -> 03/03/2026 @ 02:00 |Late call",
+			"03/07/2026 [1] {1D -> 03/08/2026} Weekend",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			if err := Exporters[test.format](&buf, []*model.CalendarObject{trip, review, late, weekend}, from, to); err != nil {
+				t.Fatal(err)
+			}
+
+			got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+
+	"tsundoku.dev/quickcal/model"
+)
+
+type htmlDay struct {
+	Date    time.Time
+	InMonth bool
+	Events  []*model.CalendarObject
+}
+
+type htmlMonth struct {
+	Title string
+	File  string
+	Weeks [][]*htmlDay
+}
+
+type htmlPage struct {
+	Title     string
+	Generated time.Time
+	Calendars []*model.Calendar
+	Months    []*htmlMonth
+	Month     *htmlMonth
+	Days      []*htmlDay
+}
+
+// HTML writes a static site into dir, with an agenda page (index.html) and a page per month between from and to.
+// The events must be expanded and sorted by start time
+func HTML(dir string, events []*model.CalendarObject, from time.Time, to time.Time) error {
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "style.css"), []byte(htmlStyle), 0o644); err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("page").Funcs(template.FuncMap{
+		"color":     htmlColor,
+		"timeRange": timeRange,
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+
+	page := htmlPage{
+		Generated: time.Now(),
+		Calendars: htmlCalendars(events),
+	}
+
+	// one page per month in the range
+	for month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location()); !month.After(to); month = month.AddDate(0, 1, 0) {
+		page.Months = append(page.Months, htmlMonthGrid(month, events))
+	}
+
+	for _, month := range page.Months {
+		monthPage := page
+		monthPage.Title = month.Title
+		monthPage.Month = month
+
+		if err := writeHTMLPage(tmpl, filepath.Join(dir, month.File), monthPage); err != nil {
+			return err
+		}
+	}
+
+	// agenda
+	agendaPage := page
+	agendaPage.Title = "Agenda"
+	for _, event := range events {
+		if len(agendaPage.Days) == 0 || !sameDay(agendaPage.Days[len(agendaPage.Days)-1].Date, *event.Start) {
+			agendaPage.Days = append(agendaPage.Days, &htmlDay{Date: *event.Start, InMonth: true})
+		}
+
+		day := agendaPage.Days[len(agendaPage.Days)-1]
+		day.Events = append(day.Events, event)
+	}
+
+	return writeHTMLPage(tmpl, filepath.Join(dir, "index.html"), agendaPage)
+}
+
+func writeHTMLPage(tmpl *template.Template, path string, page htmlPage) error {
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tmpl.Execute(file, page)
+}
+
+// htmlMonthGrid builds the weeks of a month, from Monday to Sunday, with the events that take place on each day
+func htmlMonthGrid(month time.Time, events []*model.CalendarObject) *htmlMonth {
+
+	grid := &htmlMonth{
+		Title: month.Format("January 2006"),
+		File:  month.Format("2006-01") + ".html",
+	}
+
+	// go back to the Monday of the first week
+	day := month.AddDate(0, 0, -((int(month.Weekday()) + 6) % 7))
+	for day.Month() == month.Month() || day.Before(month) {
+		week := make([]*htmlDay, 0, 7)
+		for i := 0; i < 7; i++ {
+			week = append(week, &htmlDay{
+				Date:    day,
+				InMonth: day.Month() == month.Month(),
+				Events:  eventsOnDay(events, day),
+			})
+			day = day.AddDate(0, 0, 1)
+		}
+
+		grid.Weeks = append(grid.Weeks, week)
+	}
+
+	return grid
+}
+
+// eventsOnDay returns the events that start on the given day, or that started before and are still going on
+func eventsOnDay(events []*model.CalendarObject, day time.Time) []*model.CalendarObject {
+
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+
+	onDay := make([]*model.CalendarObject, 0)
+	for _, event := range events {
+		if sameDay(*event.Start, day) || event.Start.Before(dayStart) && event.End != nil && event.End.After(dayStart) {
+			onDay = append(onDay, event)
+		}
+	}
+
+	return onDay
+}
+
+// htmlCalendars returns the distinct calendars of the events, for the legend
+func htmlCalendars(events []*model.CalendarObject) []*model.Calendar {
+
+	calendars := make([]*model.Calendar, 0)
+	seen := make(map[*model.Calendar]bool)
+	for _, event := range events {
+		if !seen[event.Calendar] {
+			seen[event.Calendar] = true
+			calendars = append(calendars, event.Calendar)
+		}
+	}
+
+	return calendars
+}
+
+// htmlColor returns the CSS color of a calendar
+func htmlColor(calendar *model.Calendar) string {
+	hex, err := model.ColorHex(calendar.ColorSpec)
+	if err != nil {
+		return "gray"
+	}
+
+	return hex
+}
+
+func sameDay(a time.Time, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="style.css">
+</head>
+<body>
+<nav>
+<a href="index.html">Agenda</a>
+{{range .Months}}<a href="{{.File}}">{{.Title}}</a>
+{{end}}</nav>
+<h1>{{.Title}}</h1>
+<ul class="legend">
+{{range .Calendars}}<li><span class="dot" style="background-color: {{color .}}"></span>{{.Name}}</li>
+{{end}}</ul>
+{{if .Month}}<table class="month">
+<thead><tr><th>Mon</th><th>Tue</th><th>Wed</th><th>Thu</th><th>Fri</th><th>Sat</th><th>Sun</th></tr></thead>
+<tbody>
+{{range .Month.Weeks}}<tr>
+{{range .}}<td{{if not .InMonth}} class="outside"{{end}}>
+<div class="day-number">{{.Date.Day}}</div>
+{{range .Events}}<div class="event" style="border-left-color: {{color .Calendar}}" title="{{.Summary}}{{if .Location}} ({{.Location}}){{end}}"><span class="time">{{if not .AllDay}}{{.Start.Format "15:04"}} {{end}}</span>{{.Summary}}</div>
+{{end}}</td>
+{{end}}</tr>
+{{end}}</tbody>
+</table>
+{{else}}{{range .Days}}<section class="agenda-day">
+<h2>{{.Date.Format "Monday, 2 January 2006"}}</h2>
+<ul>
+{{range .Events}}<li class="event" style="border-left-color: {{color .Calendar}}">
+<span class="time">{{timeRange .}}</span>
+<span class="summary">{{if .URL}}<a href="{{.URL}}">{{.Summary}}</a>{{else}}{{.Summary}}{{end}}</span>
+{{if .Location}}<span class="location">{{.Location}}</span>{{end}}
+<span class="calendar">{{.Calendar.Name}}</span>
+</li>
+{{end}}</ul>
+</section>
+{{else}}<p>No events.</p>
+{{end}}{{end}}<footer>Generated by QuickCal on {{.Generated.Format "2 January 2006 15:04"}}</footer>
+</body>
+</html>
+`
+
+const htmlStyle = `body {
+  font-family: system-ui, sans-serif;
+  margin: 0 auto;
+  max-width: 72rem;
+  padding: 1rem;
+  color: #222;
+}
+
+nav a {
+  margin-right: 0.75rem;
+}
+
+.legend {
+  list-style: none;
+  padding: 0;
+}
+
+.legend li {
+  display: inline-block;
+  margin-right: 1rem;
+}
+
+.dot {
+  display: inline-block;
+  width: 0.75rem;
+  height: 0.75rem;
+  border-radius: 50%;
+  margin-right: 0.3rem;
+}
+
+.month {
+  width: 100%;
+  border-collapse: collapse;
+  table-layout: fixed;
+}
+
+.month th, .month td {
+  border: 1px solid #ddd;
+  vertical-align: top;
+  padding: 0.25rem;
+}
+
+.month td {
+  height: 6rem;
+}
+
+.month td.outside {
+  background-color: #f5f5f5;
+  color: #999;
+}
+
+.day-number {
+  font-weight: bold;
+  font-size: 0.85rem;
+}
+
+.event {
+  border-left: 0.3rem solid gray;
+  padding-left: 0.3rem;
+  margin: 0.2rem 0;
+  font-size: 0.85rem;
+  overflow: hidden;
+  text-overflow: ellipsis;
+  white-space: nowrap;
+}
+
+.agenda-day ul {
+  list-style: none;
+  padding: 0;
+}
+
+.agenda-day .event {
+  font-size: 1rem;
+  white-space: normal;
+}
+
+.time {
+  font-variant-numeric: tabular-nums;
+  margin-right: 0.5rem;
+}
+
+.location, .calendar {
+  color: #666;
+  margin-left: 0.5rem;
+}
+
+footer {
+  margin-top: 2rem;
+  font-size: 0.8rem;
+  color: #999;
+}
+`
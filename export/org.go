@@ -0,0 +1,104 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"tsundoku.dev/quickcal/model"
+)
+
+const orgDateLayout = "2006-01-02 Mon"
+
+// Org writes the events as org-mode headings with active timestamps
+func Org(w io.Writer, events []*model.CalendarObject, from time.Time, to time.Time) error {
+
+	for _, event := range events {
+
+		entries, err := occurrences(event, from, to, orgSupports)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if _, err := fmt.Fprintf(w, "* %s\n  %s\n", oneLine(entry.Summary), OrgTimestamp(entry)); err != nil {
+				return err
+			}
+
+			if entry.Location != "" {
+				if _, err := fmt.Fprintf(w, "  Location: %s\n", oneLine(entry.Location)); err != nil {
+					return err
+				}
+			}
+
+			if entry.Description != "" {
+				if _, err := fmt.Fprintf(w, "  %s\n", strings.ReplaceAll(entry.Description, "\n", "\n  ")); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// orgSupports reports whether the recurrence rule can be written as an org repeater, which has no end date
+func orgSupports(rule *rrule.ROption) bool {
+	return rule.Freq <= rrule.DAILY && rule.Count == 0 && rule.Until.IsZero() &&
+		len(rule.Bysetpos) == 0 && len(rule.Bymonth) == 0 && len(rule.Bymonthday) == 0 && len(rule.Byyearday) == 0 &&
+		len(rule.Byweekno) == 0 && len(rule.Byweekday) == 0 && len(rule.Byhour) == 0 && len(rule.Byminute) == 0 &&
+		len(rule.Bysecond) == 0 && len(rule.Byeaster) == 0
+}
+
+// OrgTimestamp formats the event's time range as an org active timestamp, e.g. <2026-10-19 Mon 10:00-11:00>
+func OrgTimestamp(event *model.CalendarObject) string {
+
+	start := *event.Start
+
+	repeater := ""
+	if event.Recurrence != nil {
+		units := map[rrule.Frequency]string{
+			rrule.DAILY:   "d",
+			rrule.WEEKLY:  "w",
+			rrule.MONTHLY: "m",
+			rrule.YEARLY:  "y",
+		}
+		repeater = fmt.Sprintf(" +%d%s", interval(event.Recurrence), units[event.Recurrence.Freq])
+	}
+
+	if event.AllDay {
+		end := lastDay(event)
+		if end.After(start) {
+			return fmt.Sprintf("<%s%s>--<%s>", start.Format(orgDateLayout), repeater, end.Format(orgDateLayout))
+		}
+
+		return fmt.Sprintf("<%s%s>", start.Format(orgDateLayout), repeater)
+	}
+
+	start, end := timedRange(event)
+	if !end.After(start) {
+		return fmt.Sprintf("<%s %s%s>", start.Format(orgDateLayout), start.Format("15:04"), repeater)
+	}
+
+	if end.Year() == start.Year() && end.YearDay() == start.YearDay() {
+		return fmt.Sprintf("<%s %s-%s%s>", start.Format(orgDateLayout), start.Format("15:04"), end.Format("15:04"), repeater)
+	}
+
+	return fmt.Sprintf("<%s %s%s>--<%s %s>", start.Format(orgDateLayout), start.Format("15:04"), repeater,
+		end.Format(orgDateLayout), end.Format("15:04"))
+}
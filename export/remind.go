@@ -0,0 +1,135 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"tsundoku.dev/quickcal/model"
+)
+
+const remindDateLayout = "2 Jan 2006"
+
+// Remind writes the events as remind(1) reminders
+func Remind(w io.Writer, events []*model.CalendarObject, from time.Time, to time.Time) error {
+
+	for _, event := range events {
+
+		// multi-day events are written as a range of days, so recurrent ones are expanded
+		entries, err := occurrences(event, from, to, func(rule *rrule.ROption) bool {
+			return calendarRecurrence(rule, *event.Start) && !multiDay(event)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			line, err := remindLine(entry)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func remindLine(event *model.CalendarObject) (string, error) {
+
+	var ss strings.Builder
+	start, end := *event.Start, *event.Start
+	if !event.AllDay {
+		start, end = timedRange(event)
+	}
+
+	ss.WriteString("REM ")
+
+	rule := event.Recurrence
+	switch {
+	case rule == nil:
+		ss.WriteString(start.Format(remindDateLayout))
+		if multiDay(event) {
+			ss.WriteString(" THROUGH ")
+			ss.WriteString(lastDay(event).Format(remindDateLayout))
+		}
+	case rule.Freq == rrule.WEEKLY && len(rule.Byweekday) > 0:
+		for _, weekday := range rule.Byweekday {
+			ss.WriteString(weekdayNames[weekday.Day()])
+			ss.WriteString(" ")
+		}
+		ss.WriteString("FROM ")
+		ss.WriteString(start.Format(remindDateLayout))
+	case rule.Freq == rrule.DAILY:
+		ss.WriteString(fmt.Sprintf("%s *%d", start.Format(remindDateLayout), interval(rule)))
+	case rule.Freq == rrule.WEEKLY:
+		ss.WriteString(fmt.Sprintf("%s *%d", start.Format(remindDateLayout), 7*interval(rule)))
+	case rule.Freq == rrule.MONTHLY:
+		ss.WriteString(fmt.Sprintf("%d FROM %s", start.Day(), start.Format(remindDateLayout)))
+	case rule.Freq == rrule.YEARLY:
+		ss.WriteString(fmt.Sprintf("%s FROM %s", start.Format("2 Jan"), start.Format(remindDateLayout)))
+	}
+
+	if rule != nil {
+		untilDate, err := until(event)
+		if err != nil {
+			return "", err
+		}
+
+		if !event.AllDay {
+			untilDate = untilDate.In(Location)
+		}
+		if !untilDate.IsZero() {
+			ss.WriteString(" UNTIL ")
+			ss.WriteString(untilDate.Format(remindDateLayout))
+		}
+	}
+
+	if !event.AllDay {
+		ss.WriteString(" AT ")
+		ss.WriteString(start.Format("15:04"))
+
+		if end.After(start) {
+			duration := end.Sub(start)
+			ss.WriteString(fmt.Sprintf(" DURATION %d:%02d", int(duration.Hours()), int(duration.Minutes())%60))
+		}
+	}
+
+	ss.WriteString(" MSG ")
+	ss.WriteString(remindEscape(event.Summary))
+	if event.Location != "" {
+		ss.WriteString(" (")
+		ss.WriteString(remindEscape(event.Location))
+		ss.WriteString(")")
+	}
+
+	return ss.String(), nil
+}
+
+// remindEscape escapes the substitution and expression characters of a reminder body
+func remindEscape(text string) string {
+	text = strings.ReplaceAll(text, "%", "%%")
+	text = strings.ReplaceAll(text, "[", `["["]`)
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// weekdayNames is indexed like rrule.Weekday.Day(), starting on Monday
+var weekdayNames = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
@@ -0,0 +1,156 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package imip
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+)
+
+// iTIP methods
+const (
+	MethodRequest = "REQUEST"
+	MethodReply   = "REPLY"
+	MethodCancel  = "CANCEL"
+)
+
+// Message builds an RFC 6047 iMIP email, carrying a calendar object with the given iTIP method.
+// The object is attached as text/calendar, with a plain text alternative
+func Message(from string, to []string, subject string, text string, method string, cal *ical.Calendar) ([]byte, error) {
+
+	// stored calendar objects can't have a method, so it is only set on a copy
+	itip := ical.NewCalendar()
+	for name, props := range cal.Props {
+		itip.Props[name] = props
+	}
+	itip.Props.SetText(ical.PropMethod, method)
+	itip.Children = cal.Children
+
+	var calBuf bytes.Buffer
+	if err := ical.NewEncoder(&calBuf).Encode(itip); err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	textPart, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {`text/plain; charset="UTF-8"`}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := textPart.Write([]byte(text)); err != nil {
+		return nil, err
+	}
+
+	calPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf(`text/calendar; charset="UTF-8"; method=%s`, method)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := calPart.Write(calBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	fromHeader, err := formatAddress(from)
+	if err != nil {
+		return nil, err
+	}
+	toHeader := make([]string, 0, len(to))
+	for _, address := range to {
+		formatted, err := formatAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		toHeader = append(toHeader, formatted)
+	}
+
+	// line breaks would start new headers, and non-ASCII text must be encoded
+	subject = strings.Join(strings.FieldsFunc(subject, isLineBreak), " ")
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", fromHeader)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(toHeader, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@quickcal>\r\n", uuid.NewString())
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n", writer.Boundary())
+	fmt.Fprintf(&msg, "\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// formatAddress formats an email address for a header, refusing line breaks that would start new headers
+func formatAddress(address string) (string, error) {
+
+	if strings.IndexFunc(address, isLineBreak) >= 0 {
+		return "", fmt.Errorf("invalid email address %q", address)
+	}
+
+	return (&mail.Address{Address: address}).String(), nil
+}
+
+func isLineBreak(r rune) bool {
+	return r == '\r' || r == '\n'
+}
+
+// Send pipes the message to a sendmail-compatible command, such as "sendmail -t" or "msmtp -t",
+// which reads the recipients from the headers
+func Send(command string, msg []byte) error {
+
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return fmt.Errorf("no sendmail command configured")
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(msg)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// WriteEML saves the message as a .eml file in the directory, and returns its path
+func WriteEML(dir string, name string, msg []byte) (string, error) {
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, name+".eml")
+	if err := os.WriteFile(path, msg, 0o644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,83 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import (
+	"strings"
+
+	"github.com/emersion/go-ical"
+)
+
+// participation status of an attendee
+const (
+	PartStatNeedsAction = "NEEDS-ACTION"
+	PartStatAccepted    = "ACCEPTED"
+	PartStatDeclined    = "DECLINED"
+	PartStatTentative   = "TENTATIVE"
+)
+
+type Attendee struct {
+	Address  string // the calendar address, without the mailto: scheme
+	Name     string
+	Role     string
+	PartStat string
+	RSVP     bool
+}
+
+// NewAttendee reads an ATTENDEE or ORGANIZER property
+func NewAttendee(prop ical.Prop) Attendee {
+
+	attendee := Attendee{
+		Address:  Address(prop.Value),
+		Name:     prop.Params.Get(ical.ParamCommonName),
+		Role:     prop.Params.Get(ical.ParamRole),
+		PartStat: prop.Params.Get(ical.ParamParticipationStatus),
+		RSVP:     strings.EqualFold(prop.Params.Get(ical.ParamRSVP), "TRUE"),
+	}
+
+	// NEEDS-ACTION is the default participation status
+	if attendee.PartStat == "" {
+		attendee.PartStat = PartStatNeedsAction
+	}
+
+	return attendee
+}
+
+// Address removes the mailto: scheme of a calendar address
+func Address(value string) string {
+	if strings.HasPrefix(strings.ToLower(value), "mailto:") {
+		return value[len("mailto:"):]
+	}
+
+	return value
+}
+
+// SameAddress reports whether an address is one of the given addresses, ignoring case and the mailto: scheme
+func SameAddress(address string, addresses []string) bool {
+	for _, a := range addresses {
+		if strings.EqualFold(Address(address), Address(a)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a Attendee) String() string {
+	if a.Name == "" {
+		return a.Address
+	}
+
+	return a.Name + " <" + a.Address + ">"
+}
@@ -14,12 +14,33 @@ You should have received a copy of the GNU General Public License along with Qui
 package model
 
 import (
+	"strings"
+
 	"github.com/fatih/color"
 )
 
 type Calendar struct {
-	Name    string
-	Path    string
-	Color   *color.Color
-	Default bool
+	Name            string
+	Path            string
+	Color           *color.Color
+	ColorSpec       string // the color as written in the configuration file
+	Default         bool
+	Components      []string // the component types the calendar accepts, e.g. VEVENT or VTODO. Empty if unknown
+	RejectConflicts bool     // new events overlapping with existing ones are refused, e.g. for room bookings
+	ReadOnly        bool     // the calendar can't be modified, e.g. an ICS feed
+}
+
+// Supports reports whether the calendar accepts a component type. Calendars with unknown components accept any type
+func (c *Calendar) Supports(component string) bool {
+	if len(c.Components) == 0 {
+		return true
+	}
+
+	for _, supported := range c.Components {
+		if strings.EqualFold(supported, component) {
+			return true
+		}
+	}
+
+	return false
 }
@@ -16,6 +16,7 @@ package model
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/emersion/go-ical"
@@ -25,11 +26,21 @@ import (
 )
 
 type CalendarObject struct {
-	UID      string
-	Summary  string
-	Start    *time.Time
-	End      *time.Time
-	Calendar *Calendar
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       *time.Time
+	End         *time.Time
+	AllDay      bool
+	Status      string
+	Transparent bool // TRANSP:TRANSPARENT events don't block time
+	Categories  []string
+	Organizer   *Attendee
+	Attendees   []Attendee
+	Recurrence  *rrule.ROption
+	Calendar    *Calendar
 }
 
 // NewCalendarObjects creates a list of CalendarObject from a caldav.CalendarObject.
@@ -46,103 +57,243 @@ func NewCalendarObjects(calendarObject caldav.CalendarObject, from time.Time, to
 			continue
 		}
 
-		// start time
-		var startTime *time.Time
-		startProp := child.Props.Get(ical.PropDateTimeStart)
-		if startProp != nil {
-			st, err := parseTime(*startProp)
-			if err != nil {
-				return objects, err
-			}
-			startTime = &st
+		event, err := NewCalendarObject(child, fromCalendar)
+		if err != nil {
+			return objects, err
 		}
-		if startTime == nil {
+		if event == nil {
 			continue
 		}
 
-		// end time
-		var endTime *time.Time
-		endProp := child.Props.Get(ical.PropDateTimeEnd)
-		if endProp != nil {
-			et, err := parseTime(*endProp)
-			if err != nil {
-				return objects, err
-			}
-			endTime = &et
+		// return only the event if it is a regular event within the given time range
+		if event.Recurrence == nil && !event.Start.Before(from) && !event.Start.After(to) {
+			objects = append(objects, event)
+			return objects, nil
 		}
 
-		// duration
-		var eventDuration time.Duration
-		if startTime != nil && endTime != nil {
-			eventDuration = endTime.Sub(*startTime)
+		// The `expand` extension is not implemented in github.com/emersion/go-webdav/caldav, so manually expanding is required
+		// skip the event if it is not recurrent, and it is outside the time range
+		if event.Recurrence == nil {
+			continue
 		}
 
-		// uid and summary
-		eventUID := child.Props.Get(ical.PropUID).Value
-		eventSummary := child.Props.Get(ical.PropSummary).Value
-
-		// recurrence rule
-		rruleOption, err := child.Props.RecurrenceRule()
+		occurrences, err := event.Occurrences(from, to)
 		if err != nil {
 			return objects, err
 		}
 
-		// return only the event if it is a regular event within the given time range
-		if rruleOption == nil && !startTime.Before(from) && !startTime.After(to) {
-			objects = append(objects, &CalendarObject{
-				UID:      eventUID,
-				Summary:  eventSummary,
-				Start:    startTime,
-				End:      endTime,
-				Calendar: fromCalendar,
-			})
+		objects = append(objects, occurrences...)
+
+		return objects, nil
+	}
+
+	return objects, nil
+}
+
+// NewUnexpandedCalendarObjects works like NewCalendarObjects, but recurrent events are not expanded.
+// They are returned once, with their recurrence rule, if at least one of their occurrences is between from and to
+func NewUnexpandedCalendarObjects(calendarObject caldav.CalendarObject, from time.Time, to time.Time, fromCalendar *Calendar) ([]*CalendarObject, error) {
+
+	objects := make([]*CalendarObject, 0)
+	if calendarObject.Data.Component.Name != ical.CompCalendar {
+		return objects, errors.New("unexpected calendar object")
+	}
+
+	for _, child := range calendarObject.Data.Component.Children {
+		if child.Name != ical.CompEvent {
+			continue
+		}
+
+		event, err := NewCalendarObject(child, fromCalendar)
+		if err != nil {
 			return objects, err
 		}
+		if event == nil {
+			continue
+		}
 
-		// The `expand` extension is not implemented in github.com/emersion/go-webdav/caldav, so manually expanding is required
-		// skip the event if it is not recurrent, and it is outside the time range
-		if rruleOption == nil {
+		if event.Recurrence == nil {
+			if !event.Start.Before(from) && !event.Start.After(to) {
+				objects = append(objects, event)
+			}
 			continue
 		}
-		rruleOption.Dtstart = *startTime
 
-		rRule, err := rrule.NewRRule(*rruleOption)
+		occurrences, err := event.Occurrences(from, to)
 		if err != nil {
 			return objects, err
 		}
+		if len(occurrences) > 0 {
+			objects = append(objects, event)
+		}
+	}
 
-		occurrences := rRule.Between(from, to, true)
-		for _, occurrence := range occurrences {
-			if occurrence.Before(from) || occurrence.After(to) {
-				continue
-			}
+	return objects, nil
+}
 
-			occuranceEvent := &CalendarObject{
-				UID:      eventUID,
-				Summary:  eventSummary,
-				Start:    &occurrence,
-				Calendar: fromCalendar,
-			}
+// Occurrences expands a recurrent event between the provided from and to times.
+// The returned objects have no recurrence rule
+func (z *CalendarObject) Occurrences(from time.Time, to time.Time) ([]*CalendarObject, error) {
 
-			// Calculate end time if original event had one
-			if endTime != nil {
-				occurranceEndTime := occurrence.Add(eventDuration)
-				occuranceEvent.End = &occurranceEndTime
-			}
+	objects := make([]*CalendarObject, 0)
+	if z.Recurrence == nil {
+		return objects, nil
+	}
+
+	rruleOption := *z.Recurrence
+	rruleOption.Dtstart = *z.Start
+
+	rRule, err := rrule.NewRRule(rruleOption)
+	if err != nil {
+		return objects, err
+	}
+
+	// Calculate end time if original event had one
+	var eventDuration time.Duration
+	if z.End != nil {
+		eventDuration = z.End.Sub(*z.Start)
+	}
 
-			objects = append(objects, occuranceEvent)
+	occurrences := rRule.Between(from, to, true)
+	for _, occurrence := range occurrences {
+		if occurrence.Before(from) || occurrence.After(to) {
+			continue
 		}
 
-		return objects, nil
+		occurrenceStart := occurrence
+		occurrenceEvent := *z
+		occurrenceEvent.Start = &occurrenceStart
+		occurrenceEvent.Recurrence = nil
+
+		if z.End != nil {
+			occurrenceEnd := occurrence.Add(eventDuration)
+			occurrenceEvent.End = &occurrenceEnd
+		}
+
+		objects = append(objects, &occurrenceEvent)
 	}
 
 	return objects, nil
 }
 
+// IsBusy reports whether the event blocks time. Transparent and cancelled events don't
+func (z *CalendarObject) IsBusy() bool {
+	return !z.Transparent && z.Status != "CANCELLED"
+}
+
+// Duration returns the duration of the event, or 0 if it has no end
+func (z *CalendarObject) Duration() time.Duration {
+	if z.End == nil {
+		return 0
+	}
+
+	return z.End.Sub(*z.Start)
+}
+
+// Attendee returns the attendee with one of the given addresses, or nil if there is none
+func (z *CalendarObject) Attendee(addresses []string) *Attendee {
+	for i := range z.Attendees {
+		if SameAddress(z.Attendees[i].Address, addresses) {
+			return &z.Attendees[i]
+		}
+	}
+
+	return nil
+}
+
 func (z *CalendarObject) String() string {
 	return fmt.Sprintf("%s\t%v\t%s", z.Calendar.Name, z.Start, z.Summary)
 }
 
+// NewCalendarObject reads a VEVENT component. It returns nil if the event has no start time
+func NewCalendarObject(child *ical.Component, fromCalendar *Calendar) (*CalendarObject, error) {
+
+	// start time
+	startProp := child.Props.Get(ical.PropDateTimeStart)
+	if startProp == nil {
+		return nil, nil
+	}
+	startTime, err := parseTime(*startProp)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &CalendarObject{
+		Start:    &startTime,
+		AllDay:   startProp.ValueType() == ical.ValueDate,
+		Calendar: fromCalendar,
+	}
+
+	// end time, either from DTEND or DURATION
+	endProp := child.Props.Get(ical.PropDateTimeEnd)
+	durationProp := child.Props.Get(ical.PropDuration)
+	if endProp != nil {
+		endTime, err := parseTime(*endProp)
+		if err != nil {
+			return nil, err
+		}
+		event.End = &endTime
+	} else if durationProp != nil {
+		duration, err := durationProp.Duration()
+		if err != nil {
+			return nil, err
+		}
+		endTime := startTime.Add(duration)
+		event.End = &endTime
+	}
+
+	// uid, summary, description, location and url
+	event.UID = PropValue(child, ical.PropUID)
+	event.Summary = PropValue(child, ical.PropSummary)
+	event.Description = PropValue(child, ical.PropDescription)
+	event.Location = PropValue(child, ical.PropLocation)
+	event.URL = PropValue(child, ical.PropURL)
+
+	// status and time transparency
+	event.Status = PropValue(child, ical.PropStatus)
+	event.Transparent = PropValue(child, ical.PropTransparency) == "TRANSPARENT"
+
+	// categories, organizer and attendees
+	for _, prop := range child.Props.Values(ical.PropCategories) {
+		categories, err := prop.TextList()
+		if err != nil {
+			return nil, err
+		}
+		event.Categories = append(event.Categories, categories...)
+	}
+	if organizerProp := child.Props.Get(ical.PropOrganizer); organizerProp != nil {
+		organizer := NewAttendee(*organizerProp)
+		event.Organizer = &organizer
+	}
+	for _, prop := range child.Props.Values(ical.PropAttendee) {
+		event.Attendees = append(event.Attendees, NewAttendee(prop))
+	}
+
+	// recurrence rule
+	event.Recurrence, err = child.Props.RecurrenceRule()
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// PropValue returns the unescaped text value of a property, or an empty string if it is not set
+func PropValue(component *ical.Component, name string) string {
+	prop := component.Props.Get(name)
+	if prop == nil {
+		return ""
+	}
+
+	// unescaped commas are not valid in a single text value, but some clients write them anyway
+	texts, err := prop.TextList()
+	if err != nil {
+		return prop.Value
+	}
+
+	return strings.Join(texts, ",")
+}
+
 func parseTime(timeProp ical.Prop) (time.Time, error) {
 
 	// The prop param can contain either "DATE" when only the date is specified (seen in recurrent events), or TZID, for full date/time objects
@@ -171,5 +322,13 @@ func parseTime(timeProp ical.Prop) (time.Time, error) {
 		return parsedTime, nil
 	}
 
+	// UTC date/time objects end with "Z", and floating ones, without a time zone, are read in the local time zone
+	if strings.HasSuffix(timeProp.Value, "Z") {
+		return time.Parse(constants.TimeLayoutICalDateTimeUTC, timeProp.Value)
+	}
+	if len(timeProp.Value) == len(constants.TimeLayoutICalDateTime) {
+		return time.ParseInLocation(constants.TimeLayoutICalDateTime, timeProp.Value, time.Local)
+	}
+
 	return time.Time{}, errors.New("unrecognized timeProp type")
 }
@@ -13,12 +13,10 @@ You should have received a copy of the GNU General Public License along with Qui
 
 package model
 
-import (
-	"github.com/emersion/go-webdav/caldav"
-)
+import "tsundoku.dev/quickcal/backend"
 
 type CalendarServer struct {
 	Name      string
-	Client    *caldav.Client
+	Backend   backend.Backend
 	Calendars []Calendar
 }
@@ -0,0 +1,205 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ColorNames are the basic ANSI colors that can be used by name in the configuration file
+var ColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// the xterm values of the basic ANSI colors, in the same order as ColorNames
+var basicColors = [][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0}, {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+}
+
+// the extra 8 system colors of the 256-color palette
+var brightColors = [][3]uint8{
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var cubeLevels = []uint8{0, 95, 135, 175, 215, 255}
+
+// NewColor creates a terminal color from a color of the configuration file, which can be one of the ColorNames,
+// a 256-color index (0-255), or a hex value (#rrggbb or #rgb).
+// The color is downgraded to the closest one the terminal supports. An empty color returns an uncolored *color.Color
+func NewColor(spec string) (*color.Color, error) {
+
+	if spec == "" {
+		return color.New(), nil
+	}
+
+	for i, name := range ColorNames {
+		if spec == name {
+			return color.New(color.FgBlack + color.Attribute(i)), nil
+		}
+	}
+
+	rgb, index, err := parseColor(spec)
+	if err != nil {
+		return color.New(), err
+	}
+
+	switch terminalColors() {
+	case 1 << 24:
+		if index < 0 {
+			return color.New(38, 2, color.Attribute(rgb[0]), color.Attribute(rgb[1]), color.Attribute(rgb[2])), nil
+		}
+		return color.New(38, 5, color.Attribute(index)), nil
+	case 256:
+		if index < 0 {
+			index = closest256(rgb)
+		}
+		return color.New(38, 5, color.Attribute(index)), nil
+	}
+
+	return color.New(color.FgBlack + color.Attribute(closestBasic(rgb))), nil
+}
+
+// NormalizeColor validates a color of the configuration file, and converts colors with an alpha channel,
+// such as the #rrggbbaa values of the Apple calendar-color property, to #rrggbb
+func NormalizeColor(spec string) (string, error) {
+
+	spec = strings.TrimSpace(spec)
+	for _, name := range ColorNames {
+		if spec == name {
+			return spec, nil
+		}
+	}
+
+	if strings.HasPrefix(spec, "#") && len(spec) == 9 {
+		spec = spec[:7]
+	}
+
+	if _, _, err := parseColor(spec); err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(spec), nil
+}
+
+// ColorHex returns a configuration file color as a #rrggbb value
+func ColorHex(spec string) (string, error) {
+
+	for i, name := range ColorNames {
+		if spec == name {
+			rgb := basicColors[i]
+			return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]), nil
+		}
+	}
+
+	rgb, _, err := parseColor(spec)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2]), nil
+}
+
+// parseColor parses a hex color or a 256-color index. The returned index is -1 for hex colors
+func parseColor(spec string) ([3]uint8, int, error) {
+
+	if strings.HasPrefix(spec, "#") {
+		hex := spec[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 6 || err != nil {
+			return [3]uint8{}, -1, fmt.Errorf("invalid hex color '%s'", spec)
+		}
+
+		return [3]uint8{uint8(value >> 16), uint8(value >> 8), uint8(value)}, -1, nil
+	}
+
+	index, err := strconv.Atoi(spec)
+	if err != nil || index < 0 || index > 255 {
+		return [3]uint8{}, -1, fmt.Errorf("invalid color '%s', use a color name, a 256-color index or a hex value", spec)
+	}
+
+	return paletteColor(index), index, nil
+}
+
+// paletteColor returns the RGB value of a 256-color index
+func paletteColor(index int) [3]uint8 {
+
+	switch {
+	case index < 8:
+		return basicColors[index]
+	case index < 16:
+		return brightColors[index-8]
+	case index < 232:
+		index -= 16
+		return [3]uint8{cubeLevels[index/36], cubeLevels[(index/6)%6], cubeLevels[index%6]}
+	}
+
+	gray := uint8(8 + 10*(index-232))
+	return [3]uint8{gray, gray, gray}
+}
+
+// closest256 returns the index of the closest color of the 256-color palette, skipping the system colors
+// as terminals often redefine them
+func closest256(rgb [3]uint8) int {
+
+	closest := 16
+	for index := 16; index < 256; index++ {
+		if colorDistance(rgb, paletteColor(index)) < colorDistance(rgb, paletteColor(closest)) {
+			closest = index
+		}
+	}
+
+	return closest
+}
+
+// closestBasic returns the index of the closest basic ANSI color
+func closestBasic(rgb [3]uint8) int {
+
+	closest := 0
+	for index := range basicColors {
+		if colorDistance(rgb, basicColors[index]) < colorDistance(rgb, basicColors[closest]) {
+			closest = index
+		}
+	}
+
+	return closest
+}
+
+func colorDistance(a [3]uint8, b [3]uint8) int {
+	dr := int(a[0]) - int(b[0])
+	dg := int(a[1]) - int(b[1])
+	db := int(a[2]) - int(b[2])
+	return dr*dr + dg*dg + db*db
+}
+
+// terminalColors returns the number of colors supported by the terminal, based on the COLORTERM and TERM variables
+func terminalColors() int {
+
+	colorTerm := os.Getenv("COLORTERM")
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return 1 << 24
+	}
+
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return 256
+	}
+
+	return 8
+}
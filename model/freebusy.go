@@ -0,0 +1,101 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/constants"
+)
+
+// Free/busy types, from the FBTYPE parameter
+const (
+	FreeBusyBusy            = "BUSY"
+	FreeBusyBusyUnavailable = "BUSY-UNAVAILABLE"
+	FreeBusyBusyTentative   = "BUSY-TENTATIVE"
+	FreeBusyFree            = "FREE"
+)
+
+type BusyPeriod struct {
+	Start time.Time
+	End   time.Time
+	Type  string
+}
+
+// NewBusyPeriods reads the FREEBUSY properties of the VFREEBUSY components of a calendar. Free periods are skipped
+func NewBusyPeriods(cal *ical.Calendar) ([]BusyPeriod, error) {
+
+	periods := make([]BusyPeriod, 0)
+	for _, child := range cal.Children {
+		if child.Name != ical.CompFreeBusy {
+			continue
+		}
+
+		for _, prop := range child.Props.Values(ical.PropFreeBusy) {
+			fbType := strings.ToUpper(prop.Params.Get(ical.ParamFreeBusyType))
+			if fbType == "" {
+				fbType = FreeBusyBusy
+			}
+			if fbType == FreeBusyFree {
+				continue
+			}
+
+			// a single property can contain many periods, separated by commas
+			for _, value := range strings.Split(prop.Value, ",") {
+				start, end, err := parsePeriod(value)
+				if err != nil {
+					return periods, err
+				}
+
+				periods = append(periods, BusyPeriod{Start: start, End: end, Type: fbType})
+			}
+		}
+	}
+
+	return periods, nil
+}
+
+// parsePeriod parses a UTC period, either start/end or start/duration
+func parsePeriod(period string) (time.Time, time.Time, error) {
+
+	parts := strings.SplitN(strings.TrimSpace(period), "/", 2)
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period '%s'", period)
+	}
+
+	start, err := time.Parse(constants.TimeLayoutICalDateTimeUTC, parts[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if strings.HasPrefix(parts[1], "P") || strings.HasPrefix(parts[1], "+P") {
+		durationProp := ical.Prop{Name: ical.PropDuration, Params: ical.Params{}, Value: parts[1]}
+		duration, err := durationProp.Duration()
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+
+		return start, start.Add(duration), nil
+	}
+
+	end, err := time.Parse(constants.TimeLayoutICalDateTimeUTC, parts[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return start, end, nil
+}
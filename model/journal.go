@@ -0,0 +1,85 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+type Journal struct {
+	UID         string
+	Summary     string
+	Description string
+	Date        *time.Time
+	Calendar    *Calendar
+}
+
+// NewJournals creates a list of Journal from the VJOURNAL components of a caldav.CalendarObject
+func NewJournals(calendarObject caldav.CalendarObject, fromCalendar *Calendar) ([]*Journal, error) {
+
+	journals := make([]*Journal, 0)
+	if calendarObject.Data.Component.Name != ical.CompCalendar {
+		return journals, errors.New("unexpected calendar object")
+	}
+
+	for _, child := range calendarObject.Data.Component.Children {
+		if child.Name != ical.CompJournal {
+			continue
+		}
+
+		journal := &Journal{
+			UID:         PropValue(child, ical.PropUID),
+			Summary:     PropValue(child, ical.PropSummary),
+			Description: PropValue(child, ical.PropDescription),
+			Calendar:    fromCalendar,
+		}
+
+		if startProp := child.Props.Get(ical.PropDateTimeStart); startProp != nil {
+			date, err := parseTime(*startProp)
+			if err != nil {
+				return journals, err
+			}
+			journal.Date = &date
+		}
+
+		journals = append(journals, journal)
+	}
+
+	return journals, nil
+}
+
+// Title returns the summary of the entry, or the first line of its body if it has no summary
+func (j *Journal) Title() string {
+	if j.Summary != "" {
+		return j.Summary
+	}
+
+	return strings.SplitN(strings.TrimSpace(j.Description), "\n", 2)[0]
+}
+
+// Matches reports whether the summary or the body of the entry contain the text, ignoring case
+func (j *Journal) Matches(text string) bool {
+	text = strings.ToLower(text)
+	return strings.Contains(strings.ToLower(j.Summary), text) || strings.Contains(strings.ToLower(j.Description), text)
+}
+
+func (j *Journal) String() string {
+	return fmt.Sprintf("%s\t%s\t%s", j.Calendar.Name, j.Title(), j.UID)
+}
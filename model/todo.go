@@ -0,0 +1,113 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+type Todo struct {
+	UID             string
+	Summary         string
+	Description     string
+	Due             *time.Time
+	AllDay          bool
+	Priority        int
+	PercentComplete int
+	Status          string
+	Completed       *time.Time
+	Parent          string // UID of the parent task, from RELATED-TO
+	Calendar        *Calendar
+}
+
+// NewTodo reads a VTODO component
+func NewTodo(child *ical.Component, fromCalendar *Calendar) (*Todo, error) {
+
+	todo := &Todo{
+		UID:         PropValue(child, ical.PropUID),
+		Summary:     PropValue(child, ical.PropSummary),
+		Description: PropValue(child, ical.PropDescription),
+		Status:      PropValue(child, ical.PropStatus),
+		Calendar:    fromCalendar,
+	}
+
+	// parent task, RELATED-TO defaults to a PARENT relationship
+	for _, relatedTo := range child.Props.Values(ical.PropRelatedTo) {
+		relType := relatedTo.Params.Get(ical.ParamRelationshipType)
+		if relType == "" || strings.EqualFold(relType, "PARENT") {
+			todo.Parent = relatedTo.Value
+			break
+		}
+	}
+
+	// due and completion dates
+	if dueProp := child.Props.Get(ical.PropDue); dueProp != nil {
+		due, err := parseTime(*dueProp)
+		if err != nil {
+			return nil, err
+		}
+		todo.Due = &due
+		todo.AllDay = dueProp.ValueType() == ical.ValueDate
+	}
+
+	if completedProp := child.Props.Get(ical.PropCompleted); completedProp != nil {
+		completed, err := parseTime(*completedProp)
+		if err != nil {
+			return nil, err
+		}
+		todo.Completed = &completed
+	}
+
+	// priority and percent complete, which are 0 when undefined
+	if priority := PropValue(child, ical.PropPriority); priority != "" {
+		todo.Priority, _ = strconv.Atoi(priority)
+	}
+	if percent := PropValue(child, ical.PropPercentComplete); percent != "" {
+		todo.PercentComplete, _ = strconv.Atoi(percent)
+	}
+
+	return todo, nil
+}
+
+// IsOpen reports whether the task is neither completed nor cancelled
+func (t *Todo) IsOpen() bool {
+	return t.Status != "COMPLETED" && t.Status != "CANCELLED" && t.Completed == nil
+}
+
+func (t *Todo) String() string {
+
+	due := "-"
+	if t.Due != nil && t.AllDay {
+		due = t.Due.Format("Mon 02/01/2006")
+	} else if t.Due != nil {
+		due = t.Due.Format("Mon 02/01/2006 15:04")
+	}
+
+	priority := "-"
+	if t.Priority > 0 {
+		priority = fmt.Sprintf("P%d", t.Priority)
+	}
+
+	status := t.Status
+	if status == "" {
+		status = "NEEDS-ACTION"
+	}
+
+	return fmt.Sprintf("%s\t%s\t%s\t%d%%\t%s\t%s\t%s", t.Calendar.Name, due, priority, t.PercentComplete, status, t.Summary, t.UID)
+}
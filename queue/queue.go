@@ -0,0 +1,211 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/google/uuid"
+	"tsundoku.dev/quickcal/config"
+)
+
+// actions of the queued operations
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Operation is a change of a calendar object that couldn't be sent to the server
+type Operation struct {
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	Server   string    `json:"server"`
+	Calendar string    `json:"calendar"`
+	Path     string    `json:"path"`
+	ETag     string    `json:"etag,omitempty"` // the ETag the object had when it was read, for updates and deletions
+	Data     string    `json:"data,omitempty"` // the iCalendar data, for creations and updates
+}
+
+// NewOperation creates an operation on the object at path. cal is nil for deletions
+func NewOperation(action string, server string, calendar string, path string, etag string, cal *ical.Calendar) (*Operation, error) {
+
+	op := &Operation{
+		ID:       uuid.NewString(),
+		Time:     time.Now(),
+		Action:   action,
+		Server:   server,
+		Calendar: calendar,
+		Path:     path,
+		ETag:     etag,
+	}
+
+	if cal != nil {
+		var data strings.Builder
+		if err := ical.NewEncoder(&data).Encode(cal); err != nil {
+			return nil, err
+		}
+		op.Data = data.String()
+	}
+
+	return op, nil
+}
+
+// Decode returns the calendar object of a creation or update
+func (o *Operation) Decode() (*ical.Calendar, error) {
+	return ical.NewDecoder(strings.NewReader(o.Data)).Decode()
+}
+
+// ShortID returns the beginning of the ID, as shown in listings
+func (o *Operation) ShortID() string {
+	if len(o.ID) < 8 {
+		return o.ID
+	}
+
+	return o.ID[:8]
+}
+
+// Summary returns the summary of the first component of the object, if it's known
+func (o *Operation) Summary() string {
+	if o.Data == "" {
+		return ""
+	}
+
+	cal, err := o.Decode()
+	if err != nil {
+		return ""
+	}
+
+	for _, child := range cal.Children {
+		if summary := child.Props.Get(ical.PropSummary); summary != nil {
+			return summary.Value
+		}
+	}
+
+	return ""
+}
+
+// file returns the path of the queue, under the XDG state directory, since it must survive cache cleanups
+func file() (string, error) {
+
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "queue.json"), nil
+}
+
+// Load returns the queued operations, oldest first
+func Load() ([]*Operation, error) {
+
+	path, err := file()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []*Operation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, err
+	}
+
+	return ops, nil
+}
+
+// Save replaces the queued operations
+func Save(ops []*Operation) error {
+
+	path, err := file()
+	if err != nil {
+		return err
+	}
+
+	if len(ops) == 0 {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Add queues an operation. It is merged with a queued operation on the same object, so an object that was created and
+// then modified offline is only created once, one that was created and deleted offline is never sent, and one that was
+// deleted and created again is updated
+func Add(op *Operation) error {
+
+	ops, err := Load()
+	if err != nil {
+		return err
+	}
+
+	for i, queued := range ops {
+		if queued.Server != op.Server || queued.Path != op.Path {
+			continue
+		}
+
+		if queued.Action == ActionCreate && op.Action == ActionDelete {
+			return Save(append(ops[:i], ops[i+1:]...))
+		}
+
+		switch {
+		case queued.Action == ActionCreate:
+			queued.Data = op.Data
+		case op.Action == ActionCreate:
+			// the object is still on the server until the queued change is sent, so it is replaced instead
+			queued.Action = ActionUpdate
+			queued.Data = op.Data
+		default:
+			// the original ETag is kept, to detect changes made on the server since the object was read
+			queued.Action = op.Action
+			queued.Data = op.Data
+		}
+		queued.Time = op.Time
+
+		return Save(ops)
+	}
+
+	return Save(append(ops, op))
+}
@@ -0,0 +1,133 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package queue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+)
+
+func newEvent(summary string) *ical.Calendar {
+
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, "planning")
+	event.Props.SetText(ical.PropSummary, summary)
+	event.Props.SetDateTime(ical.PropDateTimeStamp, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	event.Props.SetDateTime(ical.PropDateTimeStart, time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC))
+
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//tsundoku.dev//QuickCal//EN")
+	cal.Children = append(cal.Children, event.Component)
+
+	return cal
+}
+
+type queued struct {
+	action  string
+	path    string
+	etag    string
+	summary string
+}
+
+func TestAdd(t *testing.T) {
+
+	tests := []struct {
+		name string
+		ops  []queued
+		want []queued
+	}{
+		{
+			name: "created and updated",
+			ops:  []queued{{ActionCreate, "/a.ics", "", "v1"}, {ActionUpdate, "/a.ics", "", "v2"}},
+			want: []queued{{ActionCreate, "/a.ics", "", "v2"}},
+		},
+		{
+			name: "created and deleted",
+			ops:  []queued{{ActionCreate, "/a.ics", "", "v1"}, {ActionDelete, "/a.ics", "", ""}},
+			want: []queued{},
+		},
+		{
+			name: "updated twice",
+			ops:  []queued{{ActionUpdate, "/a.ics", "1", "v1"}, {ActionUpdate, "/a.ics", "2", "v2"}},
+			want: []queued{{ActionUpdate, "/a.ics", "1", "v2"}},
+		},
+		{
+			name: "updated and deleted",
+			ops:  []queued{{ActionUpdate, "/a.ics", "1", "v1"}, {ActionDelete, "/a.ics", "2", ""}},
+			want: []queued{{ActionDelete, "/a.ics", "1", ""}},
+		},
+		{
+			name: "deleted and created",
+			ops:  []queued{{ActionDelete, "/a.ics", "1", ""}, {ActionCreate, "/a.ics", "", "v2"}},
+			want: []queued{{ActionUpdate, "/a.ics", "1", "v2"}},
+		},
+		{
+			name: "different objects",
+			ops:  []queued{{ActionCreate, "/a.ics", "", "a"}, {ActionDelete, "/b.ics", "1", ""}},
+			want: []queued{{ActionCreate, "/a.ics", "", "a"}, {ActionDelete, "/b.ics", "1", ""}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+			for _, q := range test.ops {
+				var cal *ical.Calendar
+				if q.summary != "" {
+					cal = newEvent(q.summary)
+				}
+
+				op, err := NewOperation(q.action, "server", "Personal", q.path, q.etag, cal)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := Add(op); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ops, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make([]queued, 0, len(ops))
+			for _, op := range ops {
+				got = append(got, queued{op.Action, op.Path, op.ETag, op.Summary()})
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+			for i := range got {
+				if got[i] != test.want[i] {
+					t.Errorf("got %v, want %v", got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShortID(t *testing.T) {
+
+	for id, want := range map[string]string{"": "", "abc": "abc", "0123456789": "01234567"} {
+		if got := (&Operation{ID: id}).ShortID(); got != want {
+			t.Errorf("ShortID of %q is %q, want %q", id, got, want)
+		}
+	}
+}
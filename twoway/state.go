@@ -0,0 +1,104 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package twoway
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/config"
+)
+
+// Record is the state of an object after the last sync, in the first and the second calendar
+type Record struct {
+	Paths       [2]string `json:"paths"`
+	ETags       [2]string `json:"etags"`
+	Fingerprint string    `json:"fingerprint"` // of the data written to both calendars
+}
+
+// State is the state of the objects of a pair after the last sync
+type State struct {
+	Objects map[string]Record `json:"objects"` // by UID
+
+	file string
+}
+
+// LoadState reads the state of the last sync of a pair. A pair that was never synced has an empty state
+func LoadState(name string) (*State, error) {
+
+	dir, err := config.StateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &State{
+		Objects: make(map[string]Record),
+		file:    filepath.Join(dir, "sync", url.PathEscape(name)+".json"),
+	}
+
+	data, err := os.ReadFile(state.file)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Objects == nil {
+		state.Objects = make(map[string]Record)
+	}
+
+	return state, nil
+}
+
+// Save writes the state, replacing the file atomically
+func (s *State) Save() error {
+
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, s.file)
+}
+
+// changed reports whether an object was modified in a calendar since the last sync. The ETags are compared when both
+// are known, and the data otherwise
+func (r Record) changed(side int, object caldav.CalendarObject) bool {
+
+	if object.Path != r.Paths[side] {
+		return true
+	}
+	if object.ETag != "" && r.ETags[side] != "" {
+		return object.ETag != r.ETags[side]
+	}
+
+	return Fingerprint(object.Data) != r.Fingerprint
+}
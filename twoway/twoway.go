@@ -0,0 +1,407 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package twoway
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/model"
+)
+
+// policies for the objects that changed on both sides since the last sync
+const (
+	PolicyNewest = "newest" // the version with the highest SEQUENCE, or else the latest LAST-MODIFIED, wins
+	PolicyA      = "a"      // the version of the first calendar wins
+	PolicyB      = "b"      // the version of the second calendar wins
+	PolicySkip   = "skip"   // both versions are kept and reported
+)
+
+// actions of the changes
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Side is one of the two calendars of a pair
+type Side struct {
+	Name    string
+	Backend backend.Backend
+	Path    string
+}
+
+// Change is a change written to one of the calendars
+type Change struct {
+	Action string
+	Side   string // the name of the calendar that was written
+	UID    string
+}
+
+// Result reports what a sync did
+type Result struct {
+	Changes   []Change
+	Conflicts []string
+	Errors    []error
+}
+
+// pair is a sync in progress
+type pair struct {
+	sides   [2]Side
+	state   *State
+	policy  string
+	dryRun  bool
+	result  *Result
+	objects [2]map[string]caldav.CalendarObject // by key
+}
+
+// Sync propagates the creations, updates and deletions made in each calendar since the last sync to the other one.
+// Changes are detected by comparing the ETag of every object, or its data when the ETag is unknown, with the state of
+// the last sync. When an object changed on both sides, the policy decides which version wins. With dryRun, nothing is
+// written and the state isn't modified
+func Sync(a Side, b Side, state *State, policy string, dryRun bool) (*Result, error) {
+
+	switch policy {
+	case "":
+		policy = PolicyNewest
+	case PolicyNewest, PolicyA, PolicyB, PolicySkip:
+	default:
+		return nil, fmt.Errorf("unknown conflict policy '%s', use newest, a, b or skip", policy)
+	}
+
+	p := &pair{sides: [2]Side{a, b}, state: state, policy: policy, dryRun: dryRun, result: &Result{}}
+
+	keys := make(map[string]bool)
+	for i, side := range p.sides {
+		objects, err := side.Backend.Query(side.Path, backend.Query{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read calendar '%s': %w", side.Name, err)
+		}
+
+		p.objects[i] = make(map[string]caldav.CalendarObject)
+		for _, object := range objects {
+			p.objects[i][Key(object)] = object
+			keys[Key(object)] = true
+		}
+	}
+	for key := range state.Objects {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		p.syncObject(key)
+	}
+
+	return p.result, nil
+}
+
+// syncObject syncs the versions of an object in both calendars
+func (p *pair) syncObject(key string) {
+
+	record, known := p.state.Objects[key]
+
+	var objects [2]*caldav.CalendarObject
+	var changed [2]bool
+	for i := range p.sides {
+		if object, ok := p.objects[i][key]; ok {
+			objects[i] = &object
+			changed[i] = !known || record.changed(i, object)
+		}
+	}
+
+	switch {
+	case objects[0] == nil && objects[1] == nil:
+		// deleted on both sides
+		if !p.dryRun {
+			delete(p.state.Objects, key)
+		}
+
+	case objects[0] != nil && objects[1] != nil:
+		if !changed[0] && !changed[1] {
+			return
+		}
+
+		// the same change was made on both sides, or the object was already in both calendars
+		if Fingerprint(objects[0].Data) == Fingerprint(objects[1].Data) {
+			p.record(key, objects[0], objects[1], objects[0].Data)
+			return
+		}
+
+		winner := 0
+		switch {
+		case changed[0] && changed[1]:
+			winner = p.resolve(objects)
+			if winner < 0 {
+				p.conflict(key, "changed in both calendars")
+				return
+			}
+		case changed[1]:
+			winner = 1
+		}
+
+		p.write(key, 1-winner, objects[winner], objects[1-winner])
+
+	default:
+		// the object only exists on one side: it's new there, or it was deleted on the other one
+		i := 0
+		if objects[0] == nil {
+			i = 1
+		}
+
+		if !known {
+			p.write(key, 1-i, objects[i], nil)
+			return
+		}
+
+		if !changed[i] {
+			p.delete(key, i, objects[i])
+			return
+		}
+
+		// edited on one side and deleted on the other: the edit wins, unless the policy favours the deletion
+		switch {
+		case p.policy == PolicySkip:
+			p.conflict(key, fmt.Sprintf("changed in '%s' and deleted in '%s'", p.sides[i].Name, p.sides[1-i].Name))
+		case p.policy == PolicyA && i == 1, p.policy == PolicyB && i == 0:
+			p.delete(key, i, objects[i])
+		default:
+			p.write(key, 1-i, objects[i], nil)
+		}
+	}
+}
+
+// resolve returns the side whose version wins, or -1 if the conflict must be reported
+func (p *pair) resolve(objects [2]*caldav.CalendarObject) int {
+
+	switch p.policy {
+	case PolicyA:
+		return 0
+	case PolicyB:
+		return 1
+	case PolicySkip:
+		return -1
+	}
+
+	sequences := [2]int{sequence(objects[0].Data), sequence(objects[1].Data)}
+	if sequences[0] != sequences[1] {
+		if sequences[0] > sequences[1] {
+			return 0
+		}
+		return 1
+	}
+
+	modified := [2]time.Time{lastModified(objects[0].Data), lastModified(objects[1].Data)}
+	switch {
+	case modified[0].After(modified[1]):
+		return 0
+	case modified[1].After(modified[0]):
+		return 1
+	}
+
+	return -1
+}
+
+// write copies the version of an object to the target side, replacing the existing one if any
+func (p *pair) write(key string, target int, source *caldav.CalendarObject, existing *caldav.CalendarObject) {
+
+	side := p.sides[target]
+	action := ActionCreate
+	if existing != nil {
+		action = ActionUpdate
+	}
+
+	change := Change{Action: action, Side: side.Name, UID: key}
+	if p.dryRun {
+		p.result.Changes = append(p.result.Changes, change)
+		return
+	}
+
+	var written caldav.CalendarObject
+	var err error
+	if existing != nil {
+		written.Path = existing.Path
+		written.ETag, err = side.Backend.Update(existing.Path, source.Data, existing.ETag)
+	} else {
+		written.Path = side.Path + path.Base(source.Path)
+		written.ETag, err = side.Backend.Create(written.Path, source.Data)
+	}
+	if err != nil {
+		p.failed(key, side, err)
+		return
+	}
+
+	p.result.Changes = append(p.result.Changes, change)
+	if target == 0 {
+		p.record(key, &written, source, source.Data)
+	} else {
+		p.record(key, source, &written, source.Data)
+	}
+}
+
+// delete removes an object from a side, and forgets it
+func (p *pair) delete(key string, target int, object *caldav.CalendarObject) {
+
+	side := p.sides[target]
+	change := Change{Action: ActionDelete, Side: side.Name, UID: key}
+	if p.dryRun {
+		p.result.Changes = append(p.result.Changes, change)
+		return
+	}
+
+	if err := side.Backend.Delete(object.Path, object.ETag); err != nil {
+		p.failed(key, side, err)
+		return
+	}
+
+	p.result.Changes = append(p.result.Changes, change)
+	delete(p.state.Objects, key)
+}
+
+// record saves the state of an object that is the same on both sides
+func (p *pair) record(key string, a *caldav.CalendarObject, b *caldav.CalendarObject, data *ical.Calendar) {
+	if !p.dryRun {
+		p.state.Objects[key] = Record{
+			Paths:       [2]string{a.Path, b.Path},
+			ETags:       [2]string{a.ETag, b.ETag},
+			Fingerprint: Fingerprint(data),
+		}
+	}
+}
+
+func (p *pair) conflict(key string, reason string) {
+	p.result.Conflicts = append(p.result.Conflicts, fmt.Sprintf("'%s' %s", key, reason))
+}
+
+// failed reports a write error. Objects modified during the sync are conflicts, they are synced again the next time
+func (p *pair) failed(key string, side Side, err error) {
+	if backend.IsConflict(err) {
+		p.conflict(key, fmt.Sprintf("was modified in '%s' during the sync", side.Name))
+		return
+	}
+	p.result.Errors = append(p.result.Errors, fmt.Errorf("failed to write '%s' to '%s': %w", key, side.Name, err))
+}
+
+// Key identifies an object by the UID of its components, or by its file name when it has none
+func Key(object caldav.CalendarObject) string {
+
+	for _, child := range object.Data.Children {
+		if uid := model.PropValue(child, ical.PropUID); uid != "" {
+			return uid
+		}
+	}
+
+	return path.Base(object.Path)
+}
+
+// Fingerprint returns a hash of the components of a calendar. The properties of the calendar itself, such as PRODID,
+// are ignored, so the same object has the same fingerprint on every server
+func Fingerprint(cal *ical.Calendar) string {
+
+	var buf bytes.Buffer
+	for _, child := range cal.Children {
+		if err := ical.NewEncoder(&buf).Encode(&ical.Calendar{Component: child}); err != nil {
+			// the encoder rejects invalid components, e.g. events without DTSTAMP as some servers store them
+			buf.Reset()
+			for _, child := range cal.Children {
+				writeComponent(&buf, child)
+			}
+			break
+		}
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// writeComponent writes a component without validating it, with its properties and parameters in a stable order
+func writeComponent(buf *bytes.Buffer, component *ical.Component) {
+
+	fmt.Fprintf(buf, "BEGIN:%s\n", component.Name)
+
+	names := make([]string, 0, len(component.Props))
+	for name := range component.Props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, prop := range component.Props[name] {
+			buf.WriteString(name)
+
+			paramNames := make([]string, 0, len(prop.Params))
+			for paramName := range prop.Params {
+				paramNames = append(paramNames, paramName)
+			}
+			sort.Strings(paramNames)
+			for _, paramName := range paramNames {
+				fmt.Fprintf(buf, ";%s=%q", paramName, prop.Params[paramName])
+			}
+
+			fmt.Fprintf(buf, ":%q\n", prop.Value)
+		}
+	}
+
+	for _, child := range component.Children {
+		writeComponent(buf, child)
+	}
+
+	fmt.Fprintf(buf, "END:%s\n", component.Name)
+}
+
+// sequence returns the highest SEQUENCE of the components of a calendar
+func sequence(cal *ical.Calendar) int {
+
+	highest := 0
+	for _, child := range cal.Children {
+		if value, err := strconv.Atoi(model.PropValue(child, ical.PropSequence)); err == nil && value > highest {
+			highest = value
+		}
+	}
+
+	return highest
+}
+
+// lastModified returns the latest LAST-MODIFIED, or DTSTAMP when missing, of the components of a calendar
+func lastModified(cal *ical.Calendar) time.Time {
+
+	var latest time.Time
+	for _, child := range cal.Children {
+		for _, name := range []string{ical.PropLastModified, ical.PropDateTimeStamp} {
+			modified, err := child.Props.DateTime(name, time.UTC)
+			if err == nil && !modified.IsZero() {
+				if modified.After(latest) {
+					latest = modified
+				}
+				break
+			}
+		}
+	}
+
+	return latest
+}
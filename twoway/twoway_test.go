@@ -0,0 +1,289 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package twoway
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/emersion/go-ical"
+	"tsundoku.dev/quickcal/backend"
+	"tsundoku.dev/quickcal/backend/backendtest"
+	"tsundoku.dev/quickcal/model"
+)
+
+func newEvent(uid string, summary string, sequence int) *ical.Calendar {
+
+	event := ical.NewEvent()
+	event.Props.SetText(ical.PropUID, uid)
+	event.Props.SetText(ical.PropSummary, summary)
+	event.Props.SetDateTime(ical.PropDateTimeStamp, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	event.Props.SetDateTime(ical.PropDateTimeStart, time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC))
+	event.Props.Set(&ical.Prop{Name: ical.PropSequence, Params: ical.Params{}, Value: strconv.Itoa(sequence)})
+
+	cal := ical.NewCalendar()
+	cal.Props.SetText(ical.PropVersion, "2.0")
+	cal.Props.SetText(ical.PropProductID, "-//tsundoku.dev//QuickCal//EN")
+	cal.Children = append(cal.Children, event.Component)
+
+	return cal
+}
+
+func newPair(t *testing.T) (Side, Side, *State) {
+
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	state, err := LoadState("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := Side{Name: "A", Backend: backendtest.NewMemory(backend.Calendar{Path: "/a/"}), Path: "/a/"}
+	b := Side{Name: "B", Backend: backendtest.NewMemory(backend.Calendar{Path: "/b/"}), Path: "/b/"}
+
+	return a, b, state
+}
+
+func summary(t *testing.T, side Side, path string) string {
+
+	object, err := side.Backend.Object(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return model.PropValue(object.Data.Children[0], ical.PropSummary)
+}
+
+func sync(t *testing.T, a Side, b Side, state *State, policy string) *Result {
+
+	result, err := Sync(a, b, state, policy, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors)
+	}
+
+	return result
+}
+
+func TestSyncCreatesAndDeletes(t *testing.T) {
+
+	a, b, state := newPair(t)
+	if _, err := a.Backend.Create("/a/e1.ics", newEvent("e1", "Planning", 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	result := sync(t, a, b, state, "")
+	if len(result.Changes) != 1 || result.Changes[0] != (Change{Action: ActionCreate, Side: "B", UID: "e1"}) {
+		t.Fatalf("unexpected changes %v", result.Changes)
+	}
+	if got := summary(t, b, "/b/e1.ics"); got != "Planning" {
+		t.Fatalf("got summary %q in B", got)
+	}
+
+	// nothing changed since the last sync
+	if result := sync(t, a, b, state, ""); len(result.Changes) != 0 {
+		t.Fatalf("unexpected changes %v", result.Changes)
+	}
+
+	object, err := a.Backend.Object("/a/e1.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Backend.Delete(object.Path, object.ETag); err != nil {
+		t.Fatal(err)
+	}
+
+	result = sync(t, a, b, state, "")
+	if len(result.Changes) != 1 || result.Changes[0] != (Change{Action: ActionDelete, Side: "B", UID: "e1"}) {
+		t.Fatalf("unexpected changes %v", result.Changes)
+	}
+	if _, err := b.Backend.Object("/b/e1.ics"); err == nil {
+		t.Fatal("the event wasn't deleted from B")
+	}
+}
+
+func TestSyncConflicts(t *testing.T) {
+
+	tests := []struct {
+		policy    string
+		summaryA  string
+		summaryB  string
+		conflicts int
+	}{
+		{policy: PolicyNewest, summaryA: "A", summaryB: "A"},
+		{policy: PolicyB, summaryA: "B", summaryB: "B"},
+		{policy: PolicySkip, summaryA: "A", summaryB: "B", conflicts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.policy, func(t *testing.T) {
+
+			a, b, state := newPair(t)
+			if _, err := a.Backend.Create("/a/e1.ics", newEvent("e1", "Planning", 0)); err != nil {
+				t.Fatal(err)
+			}
+			sync(t, a, b, state, test.policy)
+
+			// A has the highest sequence, so it is the newest version
+			if _, err := a.Backend.Update("/a/e1.ics", newEvent("e1", "A", 2), ""); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := b.Backend.Update("/b/e1.ics", newEvent("e1", "B", 1), ""); err != nil {
+				t.Fatal(err)
+			}
+
+			result := sync(t, a, b, state, test.policy)
+			if len(result.Conflicts) != test.conflicts {
+				t.Fatalf("got conflicts %v", result.Conflicts)
+			}
+			if got := summary(t, a, "/a/e1.ics"); got != test.summaryA {
+				t.Errorf("got summary %q in A, want %q", got, test.summaryA)
+			}
+			if got := summary(t, b, "/b/e1.ics"); got != test.summaryB {
+				t.Errorf("got summary %q in B, want %q", got, test.summaryB)
+			}
+		})
+	}
+}
+
+func TestSyncDryRun(t *testing.T) {
+
+	a, b, state := newPair(t)
+	if _, err := a.Backend.Create("/a/e1.ics", newEvent("e1", "Planning", 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Sync(a, b, state, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Changes) != 1 {
+		t.Fatalf("unexpected changes %v", result.Changes)
+	}
+	if _, err := b.Backend.Object("/b/e1.ics"); err == nil {
+		t.Fatal("the event was created in a dry run")
+	}
+	if len(state.Objects) != 0 {
+		t.Fatal("the state was modified in a dry run")
+	}
+}
+
+func TestFingerprintInvalidComponent(t *testing.T) {
+
+	// events without DTSTAMP can't be encoded, but their changes must still be detected
+	cal := newEvent("e1", "Planning", 0)
+	cal.Children[0].Props.Del(ical.PropDateTimeStamp)
+	fingerprint := Fingerprint(cal)
+
+	cal.Children[0].Props.SetText(ical.PropSummary, "Review")
+	if Fingerprint(cal) == fingerprint {
+		t.Fatal("the fingerprint didn't change with the summary")
+	}
+
+	cal.Children[0].Props.SetText(ical.PropSummary, "Planning")
+	if Fingerprint(cal) != fingerprint {
+		t.Fatal("the fingerprint isn't stable")
+	}
+}
+
+func TestSyncDeletions(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		policy    string
+		deleted   string // the side the event is deleted from: A, B or both
+		modified  bool   // whether the event is modified on the other side
+		exists    bool   // whether the event is in both calendars after the sync, or in neither
+		conflicts int
+	}{
+		{name: "deleted in A", policy: PolicyNewest, deleted: "A"},
+		{name: "deleted in B", policy: PolicyNewest, deleted: "B"},
+		{name: "deleted in both", policy: PolicyNewest, deleted: "both"},
+		{name: "deleted in A, modified in B, newest", policy: PolicyNewest, deleted: "A", modified: true, exists: true},
+		{name: "deleted in B, modified in A, newest", policy: PolicyNewest, deleted: "B", modified: true, exists: true},
+		{name: "deleted in A, modified in B, a", policy: PolicyA, deleted: "A", modified: true},
+		{name: "deleted in B, modified in A, a", policy: PolicyA, deleted: "B", modified: true, exists: true},
+		{name: "deleted in A, modified in B, b", policy: PolicyB, deleted: "A", modified: true, exists: true},
+		{name: "deleted in B, modified in A, b", policy: PolicyB, deleted: "B", modified: true},
+		{name: "deleted in A, modified in B, skip", policy: PolicySkip, deleted: "A", modified: true, conflicts: 1},
+		{name: "deleted in B, modified in A, skip", policy: PolicySkip, deleted: "B", modified: true, conflicts: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			a, b, state := newPair(t)
+			if _, err := a.Backend.Create("/a/e1.ics", newEvent("e1", "Planning", 0)); err != nil {
+				t.Fatal(err)
+			}
+			sync(t, a, b, state, test.policy)
+
+			paths := map[string]string{"A": "/a/e1.ics", "B": "/b/e1.ics"}
+			sides := map[string]Side{"A": a, "B": b}
+			other := map[string]string{"A": "B", "B": "A"}
+
+			for _, name := range []string{"A", "B"} {
+				if test.deleted != name && test.deleted != "both" {
+					continue
+				}
+				if err := sides[name].Backend.Delete(paths[name], ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if test.modified {
+				name := other[test.deleted]
+				if _, err := sides[name].Backend.Update(paths[name], newEvent("e1", "Review", 1), ""); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			result := sync(t, a, b, state, test.policy)
+			if len(result.Conflicts) != test.conflicts {
+				t.Fatalf("got conflicts %v", result.Conflicts)
+			}
+
+			if test.conflicts > 0 {
+				// both versions are kept
+				if _, err := sides[test.deleted].Backend.Object(paths[test.deleted]); err == nil {
+					t.Errorf("the event was recreated in %s", test.deleted)
+				}
+				if got := summary(t, sides[other[test.deleted]], paths[other[test.deleted]]); got != "Review" {
+					t.Errorf("got summary %q in %s", got, other[test.deleted])
+				}
+				return
+			}
+
+			for _, name := range []string{"A", "B"} {
+				_, err := sides[name].Backend.Object(paths[name])
+				switch {
+				case test.exists && err != nil:
+					t.Errorf("the event isn't in %s: %v", name, err)
+				case test.exists:
+					if got := summary(t, sides[name], paths[name]); got != "Review" {
+						t.Errorf("got summary %q in %s", got, name)
+					}
+				case err == nil:
+					t.Errorf("the event wasn't deleted from %s", name)
+				}
+			}
+
+			if _, ok := state.Objects["e1"]; ok != test.exists {
+				t.Errorf("got the event in the state: %v, want %v", ok, test.exists)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+/*
+QuickCal - A cli CalDAV client
+Copyright (C) 2025 tsundoku.dev
+
+This file is part of QuickCal.
+
+QuickCal is free software: you can redistribute it and/or modify it under the terms of the GNU General Public License as published by the Free Software Foundation, either version 3 of the License, or (at your option) any later version.
+
+QuickCal is distributed in the hope that it will be useful, but WITHOUT ANY WARRANTY; without even the implied warranty of MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License along with QuickCal. If not, see <https://www.gnu.org/licenses/>.
+*/
+
+package vdir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/emersion/go-ical"
+	"github.com/emersion/go-webdav/caldav"
+)
+
+// ErrModified is returned when an object was modified since it was read, or already exists when it is created
+var ErrModified = errors.New("the object was modified")
+
+// Storage is a vdir: a directory with a sub-directory per calendar, each containing an .ics file per object,
+// as written by vdirsyncer and read by khal
+type Storage struct {
+	root string
+}
+
+// Calendar is a calendar directory. Path is relative to the root of the storage, and ends with a slash
+type Calendar struct {
+	Path  string
+	Name  string
+	Color string
+}
+
+// New returns the storage at the given path. A leading ~ is expanded to the home directory
+func New(path string) (*Storage, error) {
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("'%s' is not a directory", path)
+	}
+
+	return &Storage{root: path}, nil
+}
+
+// Calendars returns the calendar directories. When the root directory contains .ics files and no calendar
+// directories, it is a calendar itself
+func (s *Storage) Calendars() ([]Calendar, error) {
+
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return nil, err
+	}
+
+	calendars := make([]Calendar, 0)
+	rootObjects := 0
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			calendars = append(calendars, s.calendar(entry.Name()+"/"))
+		}
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".ics") {
+			rootObjects++
+		}
+	}
+
+	// the sub-directories are the calendars, any .ics file next to them is ignored
+	if len(calendars) == 0 && rootObjects > 0 {
+		calendars = append(calendars, s.calendar("./"))
+	}
+
+	sort.Slice(calendars, func(i, j int) bool {
+		return calendars[i].Path < calendars[j].Path
+	})
+
+	return calendars, nil
+}
+
+// calendar reads the displayname and color metadata files of a calendar directory
+func (s *Storage) calendar(path string) Calendar {
+
+	calendar := Calendar{Path: path, Name: strings.TrimSuffix(path, "/")}
+	if name, err := os.ReadFile(filepath.Join(s.root, path, "displayname")); err == nil && len(bytes.TrimSpace(name)) > 0 {
+		calendar.Name = string(bytes.TrimSpace(name))
+	}
+	if color, err := os.ReadFile(filepath.Join(s.root, path, "color")); err == nil {
+		calendar.Color = string(bytes.TrimSpace(color))
+	}
+
+	return calendar
+}
+
+// Objects returns all the objects of a calendar. Files that aren't valid iCalendar are skipped
+func (s *Storage) Objects(calendarPath string) ([]caldav.CalendarObject, error) {
+
+	dir, err := s.file(calendarPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]caldav.CalendarObject, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ics") {
+			continue
+		}
+
+		object, err := s.Object(calendarPath + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		objects = append(objects, *object)
+	}
+
+	return objects, nil
+}
+
+// Object reads an object
+func (s *Storage) Object(path string) (*caldav.CalendarObject, error) {
+
+	file, err := s.file(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := ical.NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	etag, err := s.etag(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &caldav.CalendarObject{Path: path, ETag: etag, Data: cal}, nil
+}
+
+// Create writes a new object, failing with ErrModified if it already exists. It returns the ETag of the object
+func (s *Storage) Create(path string, cal *ical.Calendar) (string, error) {
+
+	file, err := s.file(path)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := s.writeTemp(file, cal)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp)
+
+	// linking fails if the file exists, so an object is never overwritten
+	if err := os.Link(tmp, file); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", ErrModified
+		}
+		return "", err
+	}
+
+	return s.etag(file)
+}
+
+// Update replaces an object, only if its ETag still matches. Without an ETag, the object is replaced unconditionally.
+// It returns the new ETag of the object
+func (s *Storage) Update(path string, cal *ical.Calendar, etag string) (string, error) {
+
+	file, err := s.file(path)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.checkETag(file, etag); err != nil {
+		return "", err
+	}
+
+	tmp, err := s.writeTemp(file, cal)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	return s.etag(file)
+}
+
+// Delete removes an object, only if its ETag still matches. Without an ETag, the object is removed unconditionally
+func (s *Storage) Delete(path string, etag string) error {
+
+	file, err := s.file(path)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkETag(file, etag); err != nil {
+		return err
+	}
+
+	return os.Remove(file)
+}
+
+// Move moves an object to another path, e.g. another calendar, failing with ErrModified if the destination exists or
+// the ETag of the object doesn't match
+func (s *Storage) Move(path string, destination string, etag string) error {
+
+	file, err := s.file(path)
+	if err != nil {
+		return err
+	}
+
+	destinationFile, err := s.file(destination)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkETag(file, etag); err != nil {
+		return err
+	}
+
+	// linking fails if the destination exists, so an object is never overwritten
+	if err := os.Link(file, destinationFile); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return ErrModified
+		}
+		return err
+	}
+
+	return os.Remove(file)
+}
+
+// file returns the file of a path relative to the root, which can't point outside of it
+func (s *Storage) file(path string) (string, error) {
+
+	cleaned := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path '%s'", path)
+	}
+
+	return filepath.Join(s.root, cleaned), nil
+}
+
+// etag derives an ETag from the modification time and size of a file
+func (s *Storage) etag(file string) (string, error) {
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x-%x", info.ModTime().UnixNano(), info.Size()), nil
+}
+
+func (s *Storage) checkETag(file string, etag string) error {
+
+	if etag == "" {
+		return nil
+	}
+
+	current, err := s.etag(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrModified
+	}
+	if err != nil {
+		return err
+	}
+	if current != etag {
+		return ErrModified
+	}
+
+	return nil
+}
+
+// writeTemp writes the object to a temporary file next to its final location, so it can be moved atomically
+func (s *Storage) writeTemp(file string, cal *ical.Calendar) (string, error) {
+
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".quickcal-*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	return tmp.Name(), nil
+}